@@ -0,0 +1,111 @@
+package goarpcsolution
+
+import (
+	"encoding/base64"
+	"errors"
+	"reflect"
+)
+
+// on wire, binary blobs are passed as objects with single field:
+//
+//	{"arpc:base64": "<base64 of data>"}
+//
+// so they can't be confused with strings or arrays after JSON round trip
+const ARPCBinaryMarker = "arpc:base64"
+
+func encodeARPCBinary(data []byte) map[string]any {
+	return map[string]any{
+		ARPCBinaryMarker: base64.StdEncoding.EncodeToString(data),
+	}
+}
+
+// ok is false if value isn't marked binary blob
+func decodeARPCBinaryMarked(value any) (data []byte, ok bool, err error) {
+	m, is_map := value.(map[string]any)
+	if !is_map || len(m) != 1 {
+		return nil, false, nil
+	}
+
+	encoded, found := m[ARPCBinaryMarker]
+	if !found {
+		return nil, false, nil
+	}
+
+	encoded_str, is_str := encoded.(string)
+	if !is_str {
+		return nil, true, errors.New("binary blob must be base64 string")
+	}
+
+	data, err = base64.StdEncoding.DecodeString(encoded_str)
+	if err != nil {
+		return nil, true, err
+	}
+
+	return data, true, nil
+}
+
+// accepts marked binary blob. []byte (if message wasn't serialized) and
+// plain base64 string (as encoding/json encodes []byte) are also accepted
+func decodeARPCBinary(value any) ([]byte, error) {
+	switch x := value.(type) {
+	case []byte:
+		return x, nil
+	case string:
+		return base64.StdEncoding.DecodeString(x)
+	}
+
+	data, ok, err := decodeARPCBinaryMarked(value)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return nil, errors.New("value isn't binary blob")
+	}
+
+	return data, nil
+}
+
+// binary values of items are replaced with marked blobs. items are copied
+func bufferItemsEncodeBinary(items []*ARPCBufferItem) []*ARPCBufferItem {
+	ret := make([]*ARPCBufferItem, 0, len(items))
+	for _, i := range items {
+		if i == nil {
+			ret = append(ret, i)
+			continue
+		}
+
+		if b, ok := i.Value.([]byte); ok {
+			x := *i
+			x.Value = encodeARPCBinary(b)
+			i = &x
+		}
+
+		ret = append(ret, i)
+	}
+	return ret
+}
+
+// decodes marked binary blobs into []byte
+func mapstructureDecodeHookBinary(
+	from reflect.Type,
+	to reflect.Type,
+	data any,
+) (any, error) {
+	if from.Kind() != reflect.Map ||
+		(to.Kind() != reflect.Interface &&
+			to != reflect.TypeOf([]byte(nil))) {
+		return data, nil
+	}
+
+	b, ok, err := decodeARPCBinaryMarked(data)
+	if err != nil {
+		return nil, err
+	}
+
+	if !ok {
+		return data, nil
+	}
+
+	return b, nil
+}
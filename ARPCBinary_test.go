@@ -0,0 +1,184 @@
+package goarpcsolution
+
+import (
+	"bytes"
+	"testing"
+)
+
+// all byte values, so data isn't valid UTF-8
+func testBinaryData() []byte {
+	ret := make([]byte, 512)
+	for i := range ret {
+		ret[i] = byte(i)
+	}
+	return ret
+}
+
+func TestDecodeARPCBinary(t *testing.T) {
+	data := []byte{0, 1, 0xfe, 0xff}
+
+	for _, i := range []struct {
+		name  string
+		value any
+		ok    bool
+	}{
+		{"bytes", data, true},
+		{"plain base64", "AAH+/w==", true},
+		{"marked", encodeARPCBinary(data), true},
+		{"marked non-string", map[string]any{ARPCBinaryMarker: 1}, false},
+		{"marked array", map[string]any{ARPCBinaryMarker: []any{0, 1}}, false},
+		{"marked invalid base64", map[string]any{ARPCBinaryMarker: "!!"}, false},
+		{"plain invalid base64", "!!", false},
+		{
+			"extra field",
+			map[string]any{ARPCBinaryMarker: "AAH+/w==", "x": 1},
+			false,
+		},
+		{"other map", map[string]any{"x": "AAH+/w=="}, false},
+		{"nil", nil, false},
+		{"number", 1, false},
+	} {
+		res, err := decodeARPCBinary(i.value)
+
+		if !i.ok {
+			if err == nil {
+				t.Errorf("%s: decoded to %v", i.name, res)
+			}
+			continue
+		}
+
+		if err != nil {
+			t.Errorf("%s: %v", i.name, err)
+			continue
+		}
+
+		if !bytes.Equal(res, data) {
+			t.Errorf("%s: decoded to %v", i.name, res)
+		}
+	}
+}
+
+func TestDecodeARPCBinaryMarked(t *testing.T) {
+	// invalid marked blob is reported as blob, so it isn't taken for
+	// ordinary object
+	_, ok, err := decodeARPCBinaryMarked(map[string]any{ARPCBinaryMarker: 1})
+	if !ok || err == nil {
+		t.Errorf("non-string marker: ok %v, err %v", ok, err)
+	}
+
+	_, ok, err = decodeARPCBinaryMarked(map[string]any{"x": 1})
+	if ok || err != nil {
+		t.Errorf("unmarked map: ok %v, err %v", ok, err)
+	}
+}
+
+func TestARPCBinaryBufferRoundTrip(t *testing.T) {
+	for _, codec := range []ARPCCodecI{ARPCCodecJSON, ARPCCodecCBOR} {
+		t.Run(
+			codec.Name(),
+			func(t *testing.T) {
+				testARPCBinaryBufferRoundTrip(t, codec)
+			},
+		)
+	}
+}
+
+func testARPCBinaryBufferRoundTrip(t *testing.T, codec ARPCCodecI) {
+	pair := newTestNodePair(t, &ARPCNodePairOptions{Codec: codec})
+	ctx := newTestContext(t)
+
+	data := testBinaryData()
+
+	buffer := NewARPCBufferMemBinary("", "")
+	_, err := buffer.Append(data[:100])
+	if err != nil {
+		t.Fatal(err)
+	}
+	_, err = buffer.Append(data[100:])
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	slice, err := pair.Node0.BufferBinaryGetSliceCtx(ctx, buffer_id, 50, 300)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(slice, data[50:300]) {
+		t.Errorf("BufferBinaryGetSlice() returned %v", slice)
+	}
+
+	items, err := pair.Node0.BufferGetItemsByIdsCtx(ctx, buffer_id, []string{"1", "0"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 {
+		t.Fatalf("%d items", len(items))
+	}
+
+	for j, expected := range [][]byte{data[100:], data[:100]} {
+		value, ok := items[j].Value.([]byte)
+		if !ok {
+			t.Errorf("item %d value is %T", j, items[j].Value)
+			continue
+		}
+		if !bytes.Equal(value, expected) {
+			t.Errorf("item %d value is %v", j, value)
+		}
+	}
+}
+
+func TestARPCBinarySocketRoundTrip(t *testing.T) {
+	for _, codec := range []ARPCCodecI{ARPCCodecJSON, ARPCCodecCBOR} {
+		t.Run(
+			codec.Name(),
+			func(t *testing.T) {
+				testARPCBinarySocketRoundTrip(t, codec)
+			},
+		)
+	}
+}
+
+func testARPCBinarySocketRoundTrip(t *testing.T, codec ARPCCodecI) {
+	pair := newTestNodePair(t, &ARPCNodePairOptions{Codec: codec})
+	ctx := newTestContext(t)
+
+	ls_id := listenTestEcho(t, pair.Ctl1)
+
+	cs_id, err := pair.Node0.SocketOpenCtx(ctx, ls_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data := testBinaryData()
+
+	n, err := pair.Node0.SocketWriteCtx(ctx, cs_id, data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n != len(data) {
+		t.Fatalf("SocketWrite() wrote %d bytes", n)
+	}
+
+	res := make([]byte, 0, len(data))
+	for len(res) != len(data) {
+		b, err := pair.Node0.SocketReadCtx(ctx, cs_id, len(data)-len(res))
+		if err != nil {
+			t.Fatal(err)
+		}
+		res = append(res, b...)
+	}
+
+	if !bytes.Equal(res, data) {
+		t.Errorf("SocketRead() returned %v", res)
+	}
+
+	err = pair.Node0.SocketCloseCtx(ctx, cs_id)
+	if err != nil {
+		t.Error(err)
+	}
+}
@@ -2,6 +2,8 @@ package goarpcsolution
 
 import (
 	"errors"
+	"reflect"
+	"time"
 
 	"github.com/AnimusPEXUS/gouuidtools"
 )
@@ -15,6 +17,7 @@ type ARPCCall struct {
 
 	ReplyErrCode uint
 	ReplyErrMsg  string
+	ReplyErrData any
 }
 
 func NewARPCCallWithId(
@@ -24,6 +27,7 @@ func NewARPCCallWithId(
 ) (*ARPCCall, error) {
 	self := new(ARPCCall)
 	self.Name = Name
+	self.CallId = CallId
 	self.Args = Args
 	err := self.IsValidError()
 	if err != nil {
@@ -59,6 +63,28 @@ func NewARPCCallReplyWithCodeAndMsg(
 	return self, nil
 }
 
+// code, msg and data are taken from ToARPCError(err).
+// nil err means reply without error
+func NewARPCCallReplyWithError(
+	ReplyToId *gouuidtools.UUID,
+
+	err error,
+
+	Args ...*ARPCCallArg,
+) (*ARPCCall, error) {
+	self, e := NewARPCCallReplyWithCodeAndMsg(ReplyToId, 0, "", Args...)
+	if e != nil {
+		return nil, e
+	}
+	if err != nil {
+		arpc_err := ToARPCError(err)
+		self.ReplyErrCode = uint(arpc_err.Code)
+		self.ReplyErrMsg = arpc_err.Message
+		self.ReplyErrData = arpc_err.Data
+	}
+	return self, nil
+}
+
 // code and msg defaults to 0 and ""
 func NewARPCCallReply(
 	ReplyToId *gouuidtools.UUID,
@@ -93,6 +119,19 @@ func (self *ARPCCall) HasErrorFields() bool {
 	return self.ReplyErrCode != 0 || self.ReplyErrMsg != ""
 }
 
+// nil if reply has no error fields. otherwise - *ARPCError with
+// ReplyErrCode, ReplyErrMsg and ReplyErrData
+func (self *ARPCCall) ReplyError() error {
+	if !self.HasErrorFields() {
+		return nil
+	}
+	code := ARPCErrorCode(self.ReplyErrCode)
+	if code == ARPCErrorCodeNone {
+		code = ARPCErrorCodeGeneric
+	}
+	return NewARPCError(code, self.ReplyErrMsg, self.ReplyErrData)
+}
+
 // HasReplyToIdField()
 func (self *ARPCCall) HasRequestFields() bool {
 	return self.HasReplyToIdField()
@@ -138,15 +177,17 @@ func (self *ARPCCall) GenARPCCallForJSON() *ARPCCallForJSON {
 		Name:         self.Name,
 		ReplyErrCode: self.ReplyErrCode,
 		ReplyErrMsg:  self.ReplyErrMsg,
+		ReplyErrData: self.ReplyErrData,
 	}
 }
 
 type ARPCCallForJSON struct {
 	CallId       *gouuidtools.UUID
-	ReplyToId    *gouuidtools.UUID `json:,omitempty`
-	Name         string            `json:,omitempty`
-	ReplyErrCode uint              `json:,omitempty`
-	ReplyErrMsg  string            `json:,omitempty`
+	ReplyToId    *gouuidtools.UUID `json:",omitempty"`
+	Name         string            `json:",omitempty"`
+	ReplyErrCode uint              `json:",omitempty"`
+	ReplyErrMsg  string            `json:",omitempty"`
+	ReplyErrData any               `json:",omitempty"`
 }
 
 type ARPCCallArgSlice struct {
@@ -171,15 +212,17 @@ type ARPCCallArg struct {
 }
 
 func (self *ARPCCallArg) IsValidError() error {
+	// note: fields can't be checked in []any loop - typed nil pointer
+	//       in interface isn't nil
 	var count uint8 = 0
-	for _, i := range []any{
-		self.Basic,
-		self.Buffer,
-		self.Transmission,
-		self.ListeningSocket,
-		self.ConnectedSocket,
+	for _, i := range []bool{
+		self.Basic != nil,
+		self.Buffer != nil,
+		self.Transmission != nil,
+		self.ListeningSocket != nil,
+		self.ConnectedSocket != nil,
 	} {
-		if i != nil {
+		if i {
 			count++
 		}
 	}
@@ -191,23 +234,26 @@ func (self *ARPCCallArg) IsValidError() error {
 		)
 	}
 
-	if self.Buffer != nil && self.Buffer.Id.IsNil() {
-		return errors.New("self.Buffer.Id == nil")
+	// nil Id is generated by controller, but then Payload is required
+
+	if self.Buffer != nil &&
+		self.Buffer.Id == nil && self.Buffer.Payload == nil {
+		return errors.New("self.Buffer have neither Id nor Payload")
 	}
 
 	if self.Transmission != nil &&
-		self.Transmission.Id.IsNil() {
-		return errors.New("self.Transmission.Id == nil")
+		self.Transmission.Id == nil && self.Transmission.Payload == nil {
+		return errors.New("self.Transmission have neither Id nor Payload")
 	}
 
 	if self.ListeningSocket != nil &&
-		self.ListeningSocket.Id.IsNil() {
-		return errors.New("self.ListeningSocket.Id == nil")
+		self.ListeningSocket.Id == nil && self.ListeningSocket.Payload == nil {
+		return errors.New("self.ListeningSocket have neither Id nor Payload")
 	}
 
 	if self.ConnectedSocket != nil &&
-		self.ConnectedSocket.Id.IsNil() {
-		return errors.New("self.ConnectedSocket.Id == nil")
+		self.ConnectedSocket.Id == nil && self.ConnectedSocket.Payload == nil {
+		return errors.New("self.ConnectedSocket have neither Id nor Payload")
 	}
 
 	return nil
@@ -217,6 +263,117 @@ func (self *ARPCCallArg) IsValid() bool {
 	return self.IsValidError() == nil
 }
 
+// generates description of arg, suitable for passing to remote node
+func (self *ARPCCallArg) GenARPCArgInfo() (*ARPCArgInfo, error) {
+	err := self.IsValidError()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &ARPCArgInfo{Name: self.Name}
+
+	switch {
+	case self.Basic != nil:
+		ret.Type = basicValueARPCArgType(self.Basic.Value)
+		ret.Value = self.Basic.Value
+	case self.Buffer != nil:
+		ret.Type = ARPCArgTypeBuffer
+		ret.Value = self.Buffer.Id.Format()
+	case self.Transmission != nil:
+		ret.Type = ARPCArgTypeTransmission
+		ret.Value = self.Transmission.Id.Format()
+	case self.ListeningSocket != nil:
+		ret.Type = ARPCArgTypeListeningSocket
+		ret.Value = self.ListeningSocket.Id.Format()
+	case self.ConnectedSocket != nil:
+		ret.Type = ARPCArgTypeConnectedSocket
+		ret.Value = self.ConnectedSocket.Id.Format()
+	}
+
+	return ret, nil
+}
+
+// wraps value into arg of type, recognized by value's type:
+// ARPCBufferI, ARPCTransmissionI, ARPCListeningSocketI and
+// ARPCConnectedSocketI become respective args with Payload (Id is
+// generated by controller), *ARPCCallArgValueType* values are used as is,
+// *ARPCCallArg is returned as is, everything else becomes basic value
+func NewARPCCallArgFromValue(value any) *ARPCCallArg {
+	ret := new(ARPCCallArg)
+
+	switch x := value.(type) {
+	case *ARPCCallArg:
+		return x
+	case *ARPCCallArgValueTypeBuffer:
+		ret.Buffer = x
+		x.OwningArg = ret
+	case *ARPCCallArgValueTypeTransmission:
+		ret.Transmission = x
+		x.OwningArg = ret
+	case *ARPCCallArgValueTypeListeningSocket:
+		ret.ListeningSocket = x
+		x.OwningArg = ret
+	case *ARPCCallArgValueTypeConnectedSocket:
+		ret.ConnectedSocket = x
+		x.OwningArg = ret
+	case ARPCBufferI:
+		ret.Buffer = &ARPCCallArgValueTypeBuffer{
+			OwningArg: ret,
+			Payload:   x,
+		}
+	case ARPCTransmissionI:
+		ret.Transmission = &ARPCCallArgValueTypeTransmission{
+			OwningArg: ret,
+			Payload:   x,
+		}
+	case ARPCListeningSocketI:
+		ret.ListeningSocket = &ARPCCallArgValueTypeListeningSocket{
+			OwningArg: ret,
+			Payload:   x,
+		}
+	case ARPCConnectedSocketI:
+		ret.ConnectedSocket = &ARPCCallArgValueTypeConnectedSocket{
+			OwningArg: ret,
+			Payload:   x,
+		}
+	default:
+		ret.Basic = &ARPCCallArgValueTypeBasic{
+			OwningArg: ret,
+			Value:     value,
+		}
+	}
+
+	return ret
+}
+
+// same as NewARPCCallArgFromValue, but arg is named
+func NewARPCCallNamedArgFromValue(name string, value any) *ARPCCallArg {
+	ret := NewARPCCallArgFromValue(value)
+	ret.Name = name
+	return ret
+}
+
+func basicValueARPCArgType(value any) ARPCArgType {
+	if value == nil {
+		return ARPCArgTypeBasicObject
+	}
+
+	switch reflect.Indirect(reflect.ValueOf(value)).Kind() {
+	case reflect.Bool:
+		return ARPCArgTypeBasicBool
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32,
+		reflect.Int64, reflect.Uint, reflect.Uint8, reflect.Uint16,
+		reflect.Uint32, reflect.Uint64, reflect.Float32, reflect.Float64:
+		return ARPCArgTypeBasicNumber
+	case reflect.String:
+		return ARPCArgTypeBasicString
+	case reflect.Slice, reflect.Array:
+		return ARPCArgTypeBasicArray
+	default:
+		return ARPCArgTypeBasicObject
+	}
+}
+
 func (self *ARPCCallArg) NullifyIds() {
 	if self.Buffer != nil {
 		self.Buffer.Id = nil
@@ -239,13 +396,18 @@ type ARPCCallArgValueTypeBasic struct {
 }
 
 // in following structs, Id used to provide predefined Id and/or to
-// return resulting Id, generated and/or used by controller
+// return resulting Id, generated and/or used by controller.
+// TTL is lifetime of object on controller without access (0 - controller's
+// default). Pinned objects are never expired
 
 type ARPCCallArgValueTypeBuffer struct {
 	OwningArg *ARPCCallArg
 
 	Id      *gouuidtools.UUID
 	Payload ARPCBufferI
+
+	TTL    time.Duration
+	Pinned bool
 }
 
 type ARPCCallArgValueTypeTransmission struct {
@@ -253,6 +415,9 @@ type ARPCCallArgValueTypeTransmission struct {
 
 	Id      *gouuidtools.UUID
 	Payload ARPCTransmissionI
+
+	TTL    time.Duration
+	Pinned bool
 }
 
 type ARPCCallArgValueTypeListeningSocket struct {
@@ -260,6 +425,9 @@ type ARPCCallArgValueTypeListeningSocket struct {
 
 	Id      *gouuidtools.UUID
 	Payload ARPCListeningSocketI
+
+	TTL    time.Duration
+	Pinned bool
 }
 
 type ARPCCallArgValueTypeConnectedSocket struct {
@@ -267,6 +435,9 @@ type ARPCCallArgValueTypeConnectedSocket struct {
 
 	Id      *gouuidtools.UUID
 	Payload ARPCConnectedSocketI
+
+	TTL    time.Duration
+	Pinned bool
 }
 
 type ARPCCallShortItem struct {
@@ -286,10 +457,14 @@ const (
 	ARPCArgTypeBuffer
 	ARPCArgTypeTransmission
 	ARPCArgTypeListeningSocket
-	ConnectedSocket
+	ARPCArgTypeConnectedSocket
 )
 
+// Value is basic value for ARPCArgTypeBasic* types and
+// formatted id string for others
 type ARPCArgInfo struct {
+	// empty for positional args
+	Name  string
 	Type  ARPCArgType
 	Value any
 }
@@ -304,6 +479,67 @@ func (self *ARPCArgInfo) IsValidError() error {
 	return nil
 }
 
+// reverse of ARPCCallArg.GenARPCArgInfo(). resulting non-basic args
+// have only Id set, without Payload
+func (self *ARPCArgInfo) GenARPCCallArg() (*ARPCCallArg, error) {
+	err := self.IsValidError()
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &ARPCCallArg{Name: self.Name}
+
+	switch self.Type {
+	case ARPCArgTypeBasicBool,
+		ARPCArgTypeBasicNumber,
+		ARPCArgTypeBasicString,
+		ARPCArgTypeBasicArray,
+		ARPCArgTypeBasicObject:
+		ret.Basic = &ARPCCallArgValueTypeBasic{
+			OwningArg: ret,
+			Value:     self.Value,
+		}
+		return ret, nil
+	}
+
+	id_str, ok := self.Value.(string)
+	if !ok {
+		return nil, errors.New("value must be id string")
+	}
+
+	id, err := gouuidtools.NewUUIDFromString(id_str)
+	if err != nil {
+		return nil, err
+	}
+
+	switch self.Type {
+	default:
+		return nil, errors.New("unsupported type")
+	case ARPCArgTypeBuffer:
+		ret.Buffer = &ARPCCallArgValueTypeBuffer{
+			OwningArg: ret,
+			Id:        id,
+		}
+	case ARPCArgTypeTransmission:
+		ret.Transmission = &ARPCCallArgValueTypeTransmission{
+			OwningArg: ret,
+			Id:        id,
+		}
+	case ARPCArgTypeListeningSocket:
+		ret.ListeningSocket = &ARPCCallArgValueTypeListeningSocket{
+			OwningArg: ret,
+			Id:        id,
+		}
+	case ARPCArgTypeConnectedSocket:
+		ret.ConnectedSocket = &ARPCCallArgValueTypeConnectedSocket{
+			OwningArg: ret,
+			Id:        id,
+		}
+	}
+
+	return ret, nil
+}
+
 func (self *ARPCArgInfo) IsValid() bool {
 	return self.IsValidError() == nil
 }
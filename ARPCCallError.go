@@ -0,0 +1,83 @@
+package goarpcsolution
+
+import (
+	"context"
+	"errors"
+	"fmt"
+)
+
+// sentinels for errors.Is() on errors returned by *Ctx() client functions
+var (
+	ErrARPCTimeout = errors.New("arpc: response timeout")
+	ErrARPCClosed  = errors.New("arpc: node closed")
+	ErrARPCRemote  = errors.New("arpc: remote error")
+)
+
+// error returned by *Ctx() client functions instead of
+// (timedout, closed, result_err, err) tuple.
+//
+// errors.Is(err, ErrARPCTimeout) - no response in time (including
+// context deadline); errors.Is(err, ErrARPCClosed) - node closed before
+// response; errors.Is(err, ErrARPCRemote) - remote node responded with
+// error. context errors and local errors are available through Unwrap()
+type ARPCCallError struct {
+	// name of ARPCNode method
+	Method string
+
+	// ErrARPCTimeout, ErrARPCClosed, ErrARPCRemote or nil for local errors
+	Kind error
+
+	Err error
+}
+
+func (self *ARPCCallError) Error() string {
+	switch {
+	case self.Kind != nil && self.Err != nil:
+		return fmt.Sprintf("%s: %s: %s", self.Method, self.Kind, self.Err)
+	case self.Kind != nil:
+		return fmt.Sprintf("%s: %s", self.Method, self.Kind)
+	default:
+		return fmt.Sprintf("%s: %s", self.Method, self.Err)
+	}
+}
+
+func (self *ARPCCallError) Is(target error) bool {
+	return self.Kind != nil && target == self.Kind
+}
+
+func (self *ARPCCallError) Unwrap() error {
+	return self.Err
+}
+
+// nil if all the values are empty
+func newARPCCallError(
+	ctx context.Context,
+	method string,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) error {
+	ret := &ARPCCallError{Method: method}
+
+	switch {
+	case err != nil:
+		ret.Err = err
+		if errors.Is(err, context.DeadlineExceeded) {
+			ret.Kind = ErrARPCTimeout
+		}
+	case result_err != nil:
+		ret.Kind = ErrARPCRemote
+		ret.Err = result_err
+	case timedout:
+		ret.Kind = ErrARPCTimeout
+		// timeout of gojsonrpc2 may be caused by bounding by ctx deadline
+		ret.Err = ctx.Err()
+	case closed:
+		ret.Kind = ErrARPCClosed
+	default:
+		return nil
+	}
+
+	return ret
+}
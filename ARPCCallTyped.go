@@ -0,0 +1,260 @@
+package goarpcsolution
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/AnimusPEXUS/gouuidtools"
+)
+
+// converts args into call args:
+//   - nil - no args;
+//   - []*ARPCCallArg - used as is;
+//   - []any - positional args;
+//   - map[string]any - named args;
+//   - struct or pointer to struct - exported fields become named args
+//     (names are taken from mapstructure tags, if present);
+//   - anything else - single positional arg.
+//
+// values are wrapped with NewARPCCallArgFromValue(), so buffers,
+// transmissions and sockets are recognized by type and get registered by
+// controller on call
+func ARPCCallArgsFrom(args any) ([]*ARPCCallArg, error) {
+	switch x := args.(type) {
+	case nil:
+		return nil, nil
+	case []*ARPCCallArg:
+		return x, nil
+	case []any:
+		ret := make([]*ARPCCallArg, 0, len(x))
+		for _, i := range x {
+			ret = append(ret, NewARPCCallArgFromValue(i))
+		}
+		return ret, nil
+	case map[string]any:
+		keys := make([]string, 0, len(x))
+		for k := range x {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+
+		ret := make([]*ARPCCallArg, 0, len(x))
+		for _, k := range keys {
+			ret = append(ret, NewARPCCallNamedArgFromValue(k, x[k]))
+		}
+		return ret, nil
+	case time.Time:
+		return []*ARPCCallArg{NewARPCCallArgFromValue(x)}, nil
+	}
+
+	// buffers, transmissions and sockets are often pointers to structs
+	arg := NewARPCCallArgFromValue(args)
+	if arg.Basic == nil {
+		return []*ARPCCallArg{arg}, nil
+	}
+
+	v := reflect.ValueOf(args)
+	if v.Kind() == reflect.Pointer && !v.IsNil() &&
+		v.Elem().Kind() == reflect.Struct {
+		v = v.Elem()
+	}
+
+	if v.Kind() != reflect.Struct {
+		return []*ARPCCallArg{arg}, nil
+	}
+
+	t := v.Type()
+	ret := make([]*ARPCCallArg, 0, t.NumField())
+
+	for i := 0; i != t.NumField(); i++ {
+		f := t.Field(i)
+		if !f.IsExported() {
+			continue
+		}
+
+		name := f.Name
+		if tag, ok := f.Tag.Lookup("mapstructure"); ok {
+			tag_name, _, _ := strings.Cut(tag, ",")
+			if tag_name == "-" {
+				continue
+			}
+			if tag_name != "" {
+				name = tag_name
+			}
+		}
+
+		ret = append(
+			ret,
+			NewARPCCallNamedArgFromValue(name, v.Field(i).Interface()),
+		)
+	}
+
+	return ret, nil
+}
+
+// makes call and waits for reply.
+// errors are *ARPCCallError (see ARPCNode's *Ctx() functions).
+// if remote side replied with error, reply is returned along with error.
+// call and reply records are closed after reply is received
+func (self *ARPCNodeCtlBasic) CallAndWait(
+	ctx context.Context,
+	name string,
+	args ...*ARPCCallArg,
+) (*ARPCCall, error) {
+
+	timedout_sig := make(chan struct{}, 1)
+	closed_sig := make(chan struct{}, 1)
+	reply_sig := make(chan *ARPCCall, 1)
+
+	handler := &ARPCNodeCtlBasicCallResHandler{
+		OnTimeout: func() { timedout_sig <- struct{}{} },
+		OnClose:   func() { closed_sig <- struct{}{} },
+		OnResponse: func(reply *ARPCCall) {
+			reply_sig <- reply
+		},
+	}
+
+	call_id, err := self.Call(name, args, false, handler)
+	if err != nil {
+		return nil, newARPCCallError(ctx, name, false, false, nil, err)
+	}
+
+	select {
+	case <-ctx.Done():
+		self.popHandler(call_id)
+		// releases args, owned by call, without waiting for expiry
+		self.CallClose(call_id)
+		return nil, newARPCCallError(ctx, name, false, false, nil, ctx.Err())
+	case <-timedout_sig:
+		return nil, newARPCCallError(ctx, name, true, false, nil, nil)
+	case <-closed_sig:
+		return nil, newARPCCallError(ctx, name, false, true, nil, nil)
+	case reply := <-reply_sig:
+		self.closeReplied(ctx, call_id, reply)
+		return reply, newARPCCallError(
+			ctx, name, false, false, reply.ReplyError(), nil,
+		)
+	}
+}
+
+// releases call and reply records, which aren't needed after reply is
+// received. reply with buffers, transmissions or sockets is left to
+// expire, as it owns them on remote side and closing it would release
+// them before caller could use them
+func (self *ARPCNodeCtlBasic) closeReplied(
+	ctx context.Context,
+	call_id *gouuidtools.UUID,
+	reply *ARPCCall,
+) {
+	self.CallClose(call_id)
+
+	for _, i := range reply.Args {
+		if i.Basic == nil {
+			return
+		}
+	}
+
+	node := self.node
+	if node == nil {
+		return
+	}
+
+	node.CallCloseCtx(ctx, reply.CallId)
+}
+
+// decodes reply args into R:
+//   - named args are decoded into R as map (R is struct or map);
+//   - single positional arg is decoded into R;
+//   - several positional args are decoded into R as list (R is slice);
+//   - no args - zero R.
+//
+// buffers, transmissions and sockets can be decoded as *ARPCCallArg,
+// respective *ARPCCallArgValueType* or *gouuidtools.UUID.
+// error of reply is returned as *ARPCError
+func DecodeARPCCallReply[R any](reply *ARPCCall) (R, error) {
+	var ret R
+
+	err := reply.ReplyError()
+	if err != nil {
+		return ret, err
+	}
+
+	named := make(map[string]any)
+	positional := make([]any, 0)
+	positional_args := make([]*ARPCCallArg, 0)
+
+	for _, i := range reply.Args {
+		var value any = i
+		if i.Basic != nil {
+			value = i.Basic.Value
+		}
+
+		if i.Name != "" {
+			named[i.Name] = value
+		} else {
+			positional = append(positional, value)
+			positional_args = append(positional_args, i)
+		}
+	}
+
+	switch {
+	case len(named) != 0:
+		err = mapstructureDecode(named, &ret, serviceArgDecodeHook)
+	case len(positional) == 0:
+	case len(positional) == 1:
+		v, err := serviceArgValue(
+			positional_args[0],
+			reflect.TypeOf(&ret).Elem(),
+		)
+		if err != nil {
+			return ret, err
+		}
+		reflect.ValueOf(&ret).Elem().Set(v)
+	default:
+		err = mapstructureDecode(positional, &ret, serviceArgDecodeHook)
+	}
+
+	return ret, err
+}
+
+// makes named call with args (see ARPCCallArgsFrom()) and decodes
+// reply into R (see DecodeARPCCallReply())
+func CallTyped[R any](
+	ctx context.Context,
+	ctl *ARPCNodeCtlBasic,
+	name string,
+	args any,
+) (R, error) {
+	var ret R
+
+	call_args, err := ARPCCallArgsFrom(args)
+	if err != nil {
+		return ret, err
+	}
+
+	reply, err := ctl.CallAndWait(ctx, name, call_args...)
+	if err != nil {
+		return ret, err
+	}
+
+	return DecodeARPCCallReply[R](reply)
+}
+
+// same as CallTyped, but reply args are ignored
+func CallTypedNoResult(
+	ctx context.Context,
+	ctl *ARPCNodeCtlBasic,
+	name string,
+	args any,
+) error {
+	call_args, err := ARPCCallArgsFrom(args)
+	if err != nil {
+		return err
+	}
+
+	_, err = ctl.CallAndWait(ctx, name, call_args...)
+	return err
+}
@@ -0,0 +1,142 @@
+package goarpcsolution
+
+import (
+	"sort"
+	"sync"
+	"time"
+)
+
+// source of time for expiry scheduling. ARPCClockSystem is used by
+// default; ARPCClockManual allows deterministic testing
+type ARPCClock interface {
+	Now() time.Time
+	AfterFunc(d time.Duration, f func()) ARPCClockTimer
+}
+
+type ARPCClockTimer interface {
+	// false if timer already fired or stopped
+	Stop() bool
+}
+
+var ARPCClockSystem ARPCClock = &xARPCClockSystem{}
+
+type xARPCClockSystem struct{}
+
+func (self *xARPCClockSystem) Now() time.Time {
+	return time.Now()
+}
+
+func (self *xARPCClockSystem) AfterFunc(
+	d time.Duration,
+	f func(),
+) ARPCClockTimer {
+	return time.AfterFunc(d, f)
+}
+
+var _ ARPCClock = &ARPCClockManual{}
+
+// clock which changes only by Advance() or Set().
+// timers are fired synchronously by Advance()/Set(), in order of their
+// time
+type ARPCClockManual struct {
+	mtx    sync.Mutex
+	now    time.Time
+	timers []*xARPCClockManualTimer
+}
+
+type xARPCClockManualTimer struct {
+	clock *ARPCClockManual
+	at    time.Time
+	f     func()
+}
+
+func NewARPCClockManual(now time.Time) *ARPCClockManual {
+	self := new(ARPCClockManual)
+	self.now = now
+	return self
+}
+
+func (self *ARPCClockManual) Now() time.Time {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	return self.now
+}
+
+func (self *ARPCClockManual) AfterFunc(
+	d time.Duration,
+	f func(),
+) ARPCClockTimer {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	t := &xARPCClockManualTimer{
+		clock: self,
+		at:    self.now.Add(d),
+		f:     f,
+	}
+
+	self.timers = append(self.timers, t)
+
+	return t
+}
+
+func (self *xARPCClockManualTimer) Stop() bool {
+	self.clock.mtx.Lock()
+	defer self.clock.mtx.Unlock()
+
+	for i, x := range self.clock.timers {
+		if x == self {
+			self.clock.timers = append(
+				self.clock.timers[:i],
+				self.clock.timers[i+1:]...,
+			)
+			return true
+		}
+	}
+
+	return false
+}
+
+// count of timers, which are not fired or stopped yet
+func (self *ARPCClockManual) PendingTimers() int {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	return len(self.timers)
+}
+
+func (self *ARPCClockManual) Advance(d time.Duration) {
+	self.Set(self.Now().Add(d))
+}
+
+// sets time and fires timers, which time came. timers, created by fired
+// timers' functions are also fired, if their time came
+func (self *ARPCClockManual) Set(now time.Time) {
+	self.mtx.Lock()
+	if now.After(self.now) {
+		self.now = now
+	}
+	self.mtx.Unlock()
+
+	for {
+		self.mtx.Lock()
+
+		sort.SliceStable(
+			self.timers,
+			func(i, j int) bool {
+				return self.timers[i].at.Before(self.timers[j].at)
+			},
+		)
+
+		if len(self.timers) == 0 || self.timers[0].at.After(self.now) {
+			self.mtx.Unlock()
+			return
+		}
+
+		t := self.timers[0]
+		self.timers = self.timers[1:]
+
+		self.mtx.Unlock()
+
+		t.f()
+	}
+}
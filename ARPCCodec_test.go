@@ -0,0 +1,138 @@
+package goarpcsolution
+
+import (
+	"bytes"
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+// JSON message, as gojsonrpc2 produces it
+func testCodecMessage(t testing.TB, blob []byte) []byte {
+	t.Helper()
+
+	msg := map[string]any{
+		"jsonrpc": "2.0",
+		"id":      "arpc-req:1",
+		"result": map[string]any{
+			"null":     nil,
+			"bool":     true,
+			"string":   "строка",
+			"int":      -5,
+			"big_uint": uint64(1) << 63,
+			"float":    1.5,
+			"array":    []any{1, "x", []any{}},
+			"blob":     encodeARPCBinary(blob),
+		},
+	}
+
+	ret, err := json.Marshal(msg)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ret
+}
+
+func TestARPCCodecMessageRoundTrip(t *testing.T) {
+	blob := testBinaryData()
+
+	for _, codec := range []ARPCCodecI{ARPCCodecJSON, ARPCCodecCBOR} {
+		msg := testCodecMessage(t, blob)
+
+		encoded, err := codecEncodeMessage(codec, msg)
+		if err != nil {
+			t.Fatalf("%s: %v", codec.Name(), err)
+		}
+
+		decoded, err := codecDecodeMessage(codec, encoded)
+		if err != nil {
+			t.Fatalf("%s: %v", codec.Name(), err)
+		}
+
+		// key order and number formatting may differ
+		expected, err := ARPCCodecJSON.Unmarshal(msg)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res, err := ARPCCodecJSON.Unmarshal(decoded)
+		if err != nil {
+			t.Fatalf("%s: %v", codec.Name(), err)
+		}
+
+		if !reflect.DeepEqual(res, expected) {
+			t.Errorf("%s: message changed:\n%s\n%s", codec.Name(), decoded, msg)
+		}
+	}
+}
+
+func TestARPCCodecCBORBinary(t *testing.T) {
+	blob := testBinaryData()
+
+	encoded, err := codecEncodeMessage(ARPCCodecCBOR, testCodecMessage(t, blob))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// blob is passed as byte string, without base64
+	if !bytes.Contains(encoded, blob) {
+		t.Error("blob isn't passed natively")
+	}
+
+	if bytes.Contains(encoded, []byte(ARPCBinaryMarker)) {
+		t.Error("marked blob is passed")
+	}
+}
+
+func TestARPCCodecCBORInvalid(t *testing.T) {
+	_, err := codecDecodeMessage(ARPCCodecCBOR, []byte{0xff, 0x00})
+	if err == nil {
+		t.Error("invalid message decoded")
+	}
+}
+
+// full cost of message with binary blob on both nodes: gojsonrpc2
+// serialization and parsing plus codec conversion (none for JSON)
+func BenchmarkARPCCodecMessage(b *testing.B) {
+	blob := bytes.Repeat([]byte{0xab}, ARPC_REMOTE_CONN_MAX_READ_SIZE)
+
+	for _, codec := range []ARPCCodecI{ARPCCodecJSON, ARPCCodecCBOR} {
+		b.Run(
+			codec.Name(),
+			func(b *testing.B) {
+				b.SetBytes(int64(len(blob)))
+				b.ReportAllocs()
+
+				wire_size := 0
+
+				for i := 0; i < b.N; i++ {
+					data := testCodecMessage(b, blob)
+
+					if codec != ARPCCodecJSON {
+						var err error
+						data, err = codecEncodeMessage(codec, data)
+						if err != nil {
+							b.Fatal(err)
+						}
+						wire_size = len(data)
+						data, err = codecDecodeMessage(codec, data)
+						if err != nil {
+							b.Fatal(err)
+						}
+					} else {
+						wire_size = len(data)
+					}
+
+					var msg map[string]any
+					err := json.Unmarshal(data, &msg)
+					if err != nil {
+						b.Fatal(err)
+					}
+				}
+
+				b.ReportMetric(float64(wire_size), "wire-bytes/op")
+			},
+		)
+	}
+}
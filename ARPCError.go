@@ -0,0 +1,201 @@
+package goarpcsolution
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	"github.com/AnimusPEXUS/gojsonrpc2"
+)
+
+// codes of ARPCError. on wire, those are passed as JSON-RPC error codes
+// (for ARPC functions) and as ReplyErrCode (for call replies).
+// zero means "no error"
+type ARPCErrorCode uint
+
+const (
+	ARPCErrorCodeNone ARPCErrorCode = iota
+
+	// error without more specific code
+	ARPCErrorCodeGeneric
+
+	// remote side failed internally. details are not disclosed
+	ARPCErrorCodeInternal
+
+	// invalid function parameters
+	ARPCErrorCodeInvalidArgument
+
+	// unknown function or not supported operation
+	ARPCErrorCodeUnsupported
+
+	// call, buffer, transmission, socket or item not found
+	ARPCErrorCodeNotFound
+
+	// object was deleted recently, because it's TTL was out
+	ARPCErrorCodeExpired
+
+	// binary operation on object buffer or vice versa
+	ARPCErrorCodeWrongBufferMode
+
+	ARPCErrorCodePermissionDenied
+
+	// object is in state, which doesn't allow operation
+	ARPCErrorCodeInvalidState
+
+	// socket states. see ARPCRemoteConn
+	ARPCErrorCodeEOF
+	ARPCErrorCodeTimeout
+	ARPCErrorCodeClosed
+)
+
+// codes starting from this, are free for use by applications
+const ARPCErrorCodeUserMin ARPCErrorCode = 1000
+
+var (
+	arpc_error_codes_mtx = new(sync.Mutex)
+	arpc_error_codes     = map[ARPCErrorCode]string{
+		ARPCErrorCodeGeneric:          "generic",
+		ARPCErrorCodeInternal:         "internal",
+		ARPCErrorCodeInvalidArgument:  "invalid argument",
+		ARPCErrorCodeUnsupported:      "unsupported",
+		ARPCErrorCodeNotFound:         "not found",
+		ARPCErrorCodeExpired:          "expired",
+		ARPCErrorCodeWrongBufferMode:  "wrong buffer mode",
+		ARPCErrorCodePermissionDenied: "permission denied",
+		ARPCErrorCodeInvalidState:     "invalid state",
+		ARPCErrorCodeEOF:              "EOF",
+		ARPCErrorCodeTimeout:          "timeout",
+		ARPCErrorCodeClosed:           "closed",
+	}
+)
+
+// register application error code, so it's name can be used in messages.
+// error if code already registered
+func RegisterARPCErrorCode(code ARPCErrorCode, name string) error {
+	if code == ARPCErrorCodeNone {
+		return errors.New("zero code can't be registered")
+	}
+
+	arpc_error_codes_mtx.Lock()
+	defer arpc_error_codes_mtx.Unlock()
+
+	if n, ok := arpc_error_codes[code]; ok {
+		return fmt.Errorf("code %d already registered as %q", code, n)
+	}
+
+	arpc_error_codes[code] = name
+
+	return nil
+}
+
+// ok is false if code isn't registered
+func GetARPCErrorCodeName(code ARPCErrorCode) (name string, ok bool) {
+	arpc_error_codes_mtx.Lock()
+	defer arpc_error_codes_mtx.Unlock()
+	name, ok = arpc_error_codes[code]
+	return
+}
+
+func (self ARPCErrorCode) String() string {
+	name, ok := GetARPCErrorCodeName(self)
+	if !ok {
+		return fmt.Sprintf("code %d", uint(self))
+	}
+	return name
+}
+
+// error which controllers can return from handlers to pass code and
+// data to remote side. remote side receives same *ARPCError.
+// errors.Is() matches ARPCErrors by Code, so ARPCErr* values can be used
+// as targets
+type ARPCError struct {
+	Code    ARPCErrorCode
+	Message string
+	Data    any
+}
+
+var (
+	ARPCErrGeneric          = &ARPCError{Code: ARPCErrorCodeGeneric}
+	ARPCErrInternal         = &ARPCError{Code: ARPCErrorCodeInternal}
+	ARPCErrInvalidArgument  = &ARPCError{Code: ARPCErrorCodeInvalidArgument}
+	ARPCErrUnsupported      = &ARPCError{Code: ARPCErrorCodeUnsupported}
+	ARPCErrNotFound         = &ARPCError{Code: ARPCErrorCodeNotFound}
+	ARPCErrExpired          = &ARPCError{Code: ARPCErrorCodeExpired}
+	ARPCErrWrongBufferMode  = &ARPCError{Code: ARPCErrorCodeWrongBufferMode}
+	ARPCErrPermissionDenied = &ARPCError{Code: ARPCErrorCodePermissionDenied}
+	ARPCErrInvalidState     = &ARPCError{Code: ARPCErrorCodeInvalidState}
+	ARPCErrEOF              = &ARPCError{Code: ARPCErrorCodeEOF}
+	ARPCErrTimeout          = &ARPCError{Code: ARPCErrorCodeTimeout}
+	ARPCErrClosed           = &ARPCError{Code: ARPCErrorCodeClosed}
+)
+
+func NewARPCError(code ARPCErrorCode, message string, data any) *ARPCError {
+	return &ARPCError{
+		Code:    code,
+		Message: message,
+		Data:    data,
+	}
+}
+
+func ARPCErrorf(code ARPCErrorCode, format string, a ...any) *ARPCError {
+	return NewARPCError(code, fmt.Sprintf(format, a...), nil)
+}
+
+func (self *ARPCError) Error() string {
+	if self.Message == "" {
+		return fmt.Sprintf("arpc error: %s", self.Code)
+	}
+	return fmt.Sprintf("arpc error: %s: %s", self.Code, self.Message)
+}
+
+func (self *ARPCError) Is(target error) bool {
+	t, ok := target.(*ARPCError)
+	return ok && t.Code == self.Code
+}
+
+// converts any error to ARPCError. errors, which are not ARPCError
+// (or doesn't wrap it), become ARPCErrorCodeGeneric
+func ToARPCError(err error) *ARPCError {
+	if err == nil {
+		return nil
+	}
+
+	var ret *ARPCError
+	if errors.As(err, &ret) {
+		return ret
+	}
+
+	return NewARPCError(ARPCErrorCodeGeneric, err.Error(), nil)
+}
+
+func (self *ARPCError) genJSONRPC2Error() *gojsonrpc2.JSONRPC2Error {
+	return &gojsonrpc2.JSONRPC2Error{
+		Code:    int(self.Code),
+		Message: self.Message,
+		Data:    self.Data,
+	}
+}
+
+// JSON-RPC protocol errors (negative codes) are mapped to closest
+// ARPCErrorCode
+func arpcErrorFromJSONRPC2Error(e *gojsonrpc2.JSONRPC2Error) *ARPCError {
+	ret := &ARPCError{
+		Message: e.Message,
+		Data:    e.Data,
+	}
+
+	switch {
+	case e.Code > 0:
+		ret.Code = ARPCErrorCode(e.Code)
+	case e.Code == int(gojsonrpc2.ProtocolErrorInvalidParams):
+		ret.Code = ARPCErrorCodeInvalidArgument
+	case e.Code == int(gojsonrpc2.ProtocolErrorMethodNotFound):
+		ret.Code = ARPCErrorCodeUnsupported
+	case e.Code == int(gojsonrpc2.ProtocolErrorInternalError):
+		ret.Code = ARPCErrorCodeInternal
+	default:
+		ret.Code = ARPCErrorCodeGeneric
+	}
+
+	return ret
+}
@@ -0,0 +1,197 @@
+package goarpcsolution
+
+import (
+	"container/heap"
+	"sync"
+	"time"
+)
+
+// calls functions at scheduled times. entries are kept in heap, and
+// only one clock timer is armed - for the nearest entry
+type xARPCExpiryScheduler struct {
+	clock ARPCClock
+
+	mtx      sync.Mutex
+	entries  xARPCExpiryHeap
+	timer    ARPCClockTimer
+	timer_at time.Time
+	closed   bool
+}
+
+type xARPCExpiryEntry struct {
+	at    time.Time
+	index int // -1 if not scheduled
+	f     func()
+}
+
+func newARPCExpiryScheduler(clock ARPCClock) *xARPCExpiryScheduler {
+	self := new(xARPCExpiryScheduler)
+	self.clock = clock
+	return self
+}
+
+func (self *xARPCExpiryScheduler) now() time.Time {
+	return self.clock.Now()
+}
+
+// creates unscheduled entry. f is called (not under scheduler's lock)
+// when time of entry comes. use reset() to schedule entry
+func (self *xARPCExpiryScheduler) newEntry(f func()) *xARPCExpiryEntry {
+	return &xARPCExpiryEntry{
+		index: -1,
+		f:     f,
+	}
+}
+
+// reschedules entry to now+d. works also for already fired or removed
+// entries
+func (self *xARPCExpiryScheduler) reset(
+	e *xARPCExpiryEntry,
+	d time.Duration,
+) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.closed {
+		return
+	}
+
+	e.at = self.clock.Now().Add(d)
+
+	if e.index == -1 {
+		heap.Push(&self.entries, e)
+	} else {
+		heap.Fix(&self.entries, e.index)
+	}
+
+	self.rearm()
+}
+
+func (self *xARPCExpiryScheduler) remove(e *xARPCExpiryEntry) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if e.index == -1 {
+		return
+	}
+
+	heap.Remove(&self.entries, e.index)
+	self.rearm()
+}
+
+// true if entry isn't scheduled and it's time came. entry, taken by
+// fire() as due, may be rescheduled before it's function is called
+func (self *xARPCExpiryScheduler) isDue(e *xARPCExpiryEntry) bool {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	return e.index == -1 && !e.at.After(self.clock.Now())
+}
+
+// count of scheduled entries
+func (self *xARPCExpiryScheduler) len() int {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	return len(self.entries)
+}
+
+// drops all entries without calling them
+func (self *xARPCExpiryScheduler) close() {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	self.closed = true
+
+	for _, e := range self.entries {
+		e.index = -1
+	}
+	self.entries = nil
+
+	if self.timer != nil {
+		self.timer.Stop()
+		self.timer = nil
+	}
+}
+
+// must be called under lock
+func (self *xARPCExpiryScheduler) rearm() {
+	if len(self.entries) == 0 {
+		if self.timer != nil {
+			self.timer.Stop()
+			self.timer = nil
+		}
+		return
+	}
+
+	at := self.entries[0].at
+
+	if self.timer != nil {
+		if self.timer_at.Equal(at) {
+			return
+		}
+		self.timer.Stop()
+	}
+
+	self.timer_at = at
+	self.timer = self.clock.AfterFunc(at.Sub(self.clock.Now()), self.fire)
+}
+
+func (self *xARPCExpiryScheduler) fire() {
+	self.mtx.Lock()
+
+	if self.closed {
+		self.mtx.Unlock()
+		return
+	}
+
+	now := self.clock.Now()
+
+	due := make([]*xARPCExpiryEntry, 0)
+	for len(self.entries) != 0 && !self.entries[0].at.After(now) {
+		due = append(due, heap.Pop(&self.entries).(*xARPCExpiryEntry))
+	}
+
+	// timer may be rearmed, while this call was waiting for lock
+	if self.timer != nil {
+		self.timer.Stop()
+		self.timer = nil
+	}
+	self.rearm()
+
+	self.mtx.Unlock()
+
+	for _, e := range due {
+		e.f()
+	}
+}
+
+type xARPCExpiryHeap []*xARPCExpiryEntry
+
+func (self xARPCExpiryHeap) Len() int {
+	return len(self)
+}
+
+func (self xARPCExpiryHeap) Less(i, j int) bool {
+	return self[i].at.Before(self[j].at)
+}
+
+func (self xARPCExpiryHeap) Swap(i, j int) {
+	self[i], self[j] = self[j], self[i]
+	self[i].index = i
+	self[j].index = j
+}
+
+func (self *xARPCExpiryHeap) Push(x any) {
+	e := x.(*xARPCExpiryEntry)
+	e.index = len(*self)
+	*self = append(*self, e)
+}
+
+func (self *xARPCExpiryHeap) Pop() any {
+	old := *self
+	n := len(old)
+	e := old[n-1]
+	old[n-1] = nil
+	e.index = -1
+	*self = old[:n-1]
+	return e
+}
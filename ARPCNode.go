@@ -1,9 +1,15 @@
 package goarpcsolution
 
 import (
+	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
+	"reflect"
+	"strconv"
 	"strings"
+	"sync"
+	"sync/atomic"
 	"time"
 
 	"github.com/AnimusPEXUS/gojsonrpc2"
@@ -28,15 +34,39 @@ const ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN_LEN = len(ARPC_MSG_PREFIX_ARPC_PLUS_COLUM
 type ARPCNode struct {
 	PushMessageToOutsideCB func(data []byte) error
 
+	// some handlers (like NewCall) are running in separate goroutines,
+	// so their errors can't be returned to PushMessageFromOutside caller.
+	// such errors are passed here.
+	//   error #0 - if protocol error
+	//   error #1 - other errors
+	OnAsyncHandlerErrorsCB func(error, error)
+
+	// timeout for *Ctx() client functions, used if context has no
+	// earlier deadline. default is one minute
+	CtxResponseTimeout time.Duration
+
 	controller ARPCNodeCtlI
 
 	jrpc_node *gojsonrpc2.JSONRPC2Node
 
+	codec_mtx sync.Mutex
+	codec     ARPCCodecI
+
 	debugName string
 
 	debug bool
 
-	stop_flag bool
+	stop_flag atomic.Bool
+
+	// requests of client functions by id. responses to them are routed
+	// by ARPCNode itself (see sendRequest())
+	pending_mtx     sync.Mutex
+	pending         map[string]*xARPCNodePendingRequest
+	next_request_id uint64
+
+	// followers of remote buffers by buffer id (see BufferFollow())
+	followers_mtx sync.Mutex
+	followers     map[string][]*ARPCRemoteBufferFollower
 
 	closeRecursionGuard *gorecursionguard.RecursionGuard
 }
@@ -52,21 +82,36 @@ func NewARPCNode(
 	)
 
 	self.debugName = "ARPCNode"
+	self.pending = make(map[string]*xARPCNodePendingRequest)
+	self.followers = make(map[string][]*ARPCRemoteBufferFollower)
+	self.CtxResponseTimeout = time.Minute
 	self.controller = controller
 	self.controller.SetNode(self)
 
 	self.jrpc_node = gojsonrpc2.NewJSONRPC2Node()
+	self.codec = ARPCCodecJSON
+
 	self.jrpc_node.PushMessageToOutsideCB = func(data []byte) error {
 		if self.PushMessageToOutsideCB == nil {
 			return errors.New("ARPCNode.PushMessageToOutsideCB == nil")
 		}
+
+		codec := self.GetCodec()
+		if codec != ARPCCodecJSON {
+			var err error
+			data, err = codecEncodeMessage(codec, data)
+			if err != nil {
+				return err
+			}
+		}
+
 		return self.PushMessageToOutsideCB(data)
 	}
 
 	self.jrpc_node.OnRequestCB =
 		func(msg *gojsonrpc2.Message) (error, error) {
 			if self.debug {
-				self.DebugPrintfln("jrpc_node.OnRequestCB msg:", msg)
+				self.DebugPrintln("jrpc_node.OnRequestCB msg:", msg)
 			}
 			return self.handleJRPCNodeMessage(msg)
 		}
@@ -103,7 +148,7 @@ func (self *ARPCNode) DebugPrintfln(format string, data ...any) {
 }
 
 func (self *ARPCNode) nodeInvalidStateException() {
-	if self.stop_flag || self.controller == nil {
+	if self.stop_flag.Load() || self.controller == nil {
 		panic("node is in invalid state")
 	}
 }
@@ -112,28 +157,59 @@ func (self *ARPCNode) GetController() ARPCNodeCtlI {
 	return self.controller
 }
 
-// func (self *ARPCNode) IsStopFlag() bool {
-// 	return self.stop_flag
-// }
+// sets encoding of messages, passed to PushMessageToOutsideCB and
+// accepted by PushMessageFromOutside. nil - ARPCCodecJSON (default).
+// remote node must use same codec
+func (self *ARPCNode) SetCodec(codec ARPCCodecI) {
+	if codec == nil {
+		codec = ARPCCodecJSON
+	}
+	self.codec_mtx.Lock()
+	defer self.codec_mtx.Unlock()
+	self.codec = codec
+}
+
+func (self *ARPCNode) GetCodec() ARPCCodecI {
+	self.codec_mtx.Lock()
+	defer self.codec_mtx.Unlock()
+	return self.codec
+}
+
+// true after Close()
+func (self *ARPCNode) IsClosed() bool {
+	return self.stop_flag.Load()
+}
 
 // if controller is set - calls it's Close();
 // if jrpc2 node is set - calls it's Close();
-// sets this node into invalid state.
+// sets this node into invalid state: requests and notifications return
+// closed errors, incomming messages are rejected.
 // node can't be reused after Close() and should be replaced.
 func (self *ARPCNode) Close() {
 	self.closeRecursionGuard.Do(
 		func() {
 
-			self.stop_flag = true
+			self.bufferFollowersStop()
+
+			self.stop_flag.Store(true)
 
 			if self.controller != nil {
 				self.controller.Close()
-				self.controller = nil
 			}
 
 			if self.jrpc_node != nil {
 				self.jrpc_node.Close()
-				self.jrpc_node = nil
+			}
+
+			self.pending_mtx.Lock()
+			pending := make([]*xARPCNodePendingRequest, 0, len(self.pending))
+			for _, i := range self.pending {
+				pending = append(pending, i)
+			}
+			self.pending_mtx.Unlock()
+
+			for _, i := range pending {
+				i.closed()
 			}
 		},
 	)
@@ -221,9 +297,27 @@ func (self *ARPCNode) SendError(msg *gojsonrpc2.Message) error {
 // ----------------------------------------
 
 // #0 protocol violation - not critical for server running,
-// #1 error - should be treated as server errors
+// #1 error - should be treated as server errors.
+// after Close() returns ErrARPCClosed as #1
 func (self *ARPCNode) PushMessageFromOutside(data []byte) (error, error) {
-	self.nodeInvalidStateException()
+	if self.IsClosed() {
+		return nil, ErrARPCClosed
+	}
+
+	codec := self.GetCodec()
+	if codec != ARPCCodecJSON {
+		var err error
+		data, err = codecDecodeMessage(codec, data)
+		if err != nil {
+			return err, errors.New("protocol error")
+		}
+	}
+
+	routed, err_proto, err := self.routeResponse(data)
+	if routed {
+		return err_proto, err
+	}
+
 	return self.jrpc_node.PushMessageFromOutside(data)
 }
 
@@ -304,8 +398,6 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 		default:
 			err_code = int(gojsonrpc2.ProtocolErrorMethodNotFound)
 			err_input = errors.New("invalid method name")
-			err_processing_not_internal = errors.New("protocol error")
-			err_processing_internal = errors.New("protocol error")
 
 		case "NewCall":
 			call_id, not_found, err :=
@@ -332,7 +424,7 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			response_on, response_on_found, err :=
+			response_on, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
@@ -346,7 +438,7 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 			}
 
 			var response_on_uuid *gouuidtools.UUID
-			if response_on_found {
+			if !not_found {
 				response_on_uuid, err = gouuidtools.NewUUIDFromString(response_on)
 				if err != nil {
 					err_input = err
@@ -354,46 +446,46 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				}
 			}
 
-			if !response_on_found {
-				go self.controller.NewCall(
-					call_id_uuid,
-					nil,
-				)
-			} else {
-				go self.controller.NewCall(
-					call_id_uuid,
-					response_on_uuid,
+			// controller may need to make requests to this node to
+			// handle new call, so it can't be handled synchronously
+			go func(controller ARPCNodeCtlI) {
+				self.asyncHandlerResult(
+					controller.NewCall(
+						call_id_uuid,
+						response_on_uuid,
+					),
 				)
-			}
+			}(self.controller)
 
-		// case "NewBuffer":
-		// 	buffer_id, not_found, err :=
-		// 		anyutils.TraverseObjectTree002_string(
-		// 			msg_par,
-		// 			true,
-		// 			true,
-		// 			"buffer_id",
-		// 		)
+		case "NewBuffer":
+			buffer_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"buffer_id",
+				)
 
-		// 	if not_found {
-		// 		err_input = errors.New("not found required parameter buffer_id")
-		// 		break
-		// 	}
+			if not_found {
+				err_input =
+					errors.New("not found required parameter buffer_id")
+				break
+			}
 
-		// 	if err != nil {
-		// 		err_processing_internal = err
-		// 		break
-		// 	}
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
 
-		// 	buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
-		// 	if err != nil {
-		// 		err_input = err
-		// 		break
-		// 	}
+			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
+			if err != nil {
+				err_input = err
+				break
+			}
 
-		// self.controller.NewBuffer(
-		// 	buffer_id_uuid,
-		// )
+			self.controller.NewBuffer(
+				buffer_id_uuid,
+			)
 
 		case "BufferUpdated":
 			buffer_id, not_found, err :=
@@ -420,6 +512,8 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
+			self.bufferFollowersNotify(buffer_id_uuid, false, false)
+
 			self.controller.BufferUpdated(
 				buffer_id_uuid,
 			)
@@ -455,52 +549,18 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				tarnsmission_id_uuid,
 			)
 
-		// case "NewSocket":
-		// 	port_id, not_found, err :=
-		// 		anyutils.TraverseObjectTree002_string(
-		// 			msg_par,
-		// 			true,
-		// 			true,
-		// 			"port_id",
-		// 		)
-
-		// 	if not_found {
-		// 		err_input = errors.New("not found required parameter port_id")
-		// 		break
-		// 	}
-
-		// 	if err != nil {
-		// 		err_processing_internal = err
-		// 		break
-		// 	}
-
-		// 	port_id_uuid, err := gouuidtools.NewUUIDFromString(port_id)
-		// 	if err != nil {
-		// 		err_input = err
-		// 		break
-		// 	}
-
-		// 	self.controller.NewSocket(
-		// 		port_id_uuid,
-		// 	)
-
-		// ------------ Methods ------------
-
-		case "CallGetList":
-			result, err_processing_not_internal, err_processing_internal =
-				self.controller.CallGetList()
-
-		case "CallGetArgCount":
-			call_id, not_found, err :=
+		case "ObjectFinished":
+			object_type, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"call_id",
+					"object_type",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter call_id")
+				err_input =
+					errors.New("not found required parameter object_type")
 				break
 			}
 
@@ -509,27 +569,17 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			call_id_uuid, err := gouuidtools.NewUUIDFromString(call_id)
-			if err != nil {
-				err_input = err
-				break
-			}
-
-			result, err_processing_not_internal, err_processing_internal =
-				self.controller.CallGetArgCount(
-					call_id_uuid,
+			object_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"object_id",
 				)
 
-		case "CallGetArgValue":
-			call_id, not_found, err := anyutils.TraverseObjectTree002_string(
-				msg_par,
-				true,
-				true,
-				"call_id",
-			)
-
 			if not_found {
-				err_input = errors.New("not found required parameter call_id")
+				err_input =
+					errors.New("not found required parameter object_id")
 				break
 			}
 
@@ -538,43 +588,33 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			call_id_uuid, err := gouuidtools.NewUUIDFromString(call_id)
+			object_id_uuid, err := gouuidtools.NewUUIDFromString(object_id)
 			if err != nil {
 				err_input = err
 				break
 			}
 
-			first, not_found, err := anyutils.TraverseObjectTree002_int(
-				msg_par,
-				true,
-				true,
-				"first",
-			)
-
-			if not_found {
-				err_input = errors.New("not found required parameter 'first'")
-				break
-			}
-
-			if err != nil {
-				err_processing_internal = err
-				break
-			}
-
-			if first < 0 {
-				err_input = errors.New("first must be >= 0")
-				break
+			if ARPCObjectType(object_type) == ARPCObjectTypeBuffer {
+				self.bufferFollowersNotify(object_id_uuid, true, false)
 			}
 
-			last, not_found, err := anyutils.TraverseObjectTree002_int(
-				msg_par,
-				true,
-				true,
-				"first",
+			self.controller.ObjectFinished(
+				ARPCObjectType(object_type),
+				object_id_uuid,
 			)
 
+		case "ObjectRemoved":
+			object_type, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"object_type",
+				)
+
 			if not_found {
-				err_input = errors.New("not found required parameter 'last'")
+				err_input =
+					errors.New("not found required parameter object_type")
 				break
 			}
 
@@ -583,28 +623,17 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			if last < first {
-				err_input = errors.New("last must be >= first")
-				break
-			}
-
-			result, err_processing_not_internal, err_processing_internal =
-				self.controller.CallGetArgValues(
-					call_id_uuid,
-					first, last,
-				)
-
-		case "CallClose":
-			call_id, not_found, err :=
+			object_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"call_id",
+					"object_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter call_id")
+				err_input =
+					errors.New("not found required parameter object_id")
 				break
 			}
 
@@ -613,27 +642,22 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			call_id_uuid, err := gouuidtools.NewUUIDFromString(call_id)
+			object_id_uuid, err := gouuidtools.NewUUIDFromString(object_id)
 			if err != nil {
 				err_input = err
 				break
 			}
 
-			self.controller.CallClose(
-				call_id_uuid,
-			)
-
-		case "BufferGetInfo":
-			buffer_id, not_found, err :=
+			reason, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"buffer_id",
+					"reason",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter buffer_id")
+				err_input = errors.New("not found required parameter reason")
 				break
 			}
 
@@ -642,28 +666,28 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
-			if err != nil {
-				err_input = err
-				break
+			if ARPCObjectType(object_type) == ARPCObjectTypeBuffer {
+				self.bufferFollowersNotify(object_id_uuid, false, true)
 			}
 
-			result, err_processing_not_internal, err_processing_internal =
-				self.controller.BufferGetInfo(
-					buffer_id_uuid,
-				)
+			self.controller.ObjectRemoved(
+				ARPCObjectType(object_type),
+				object_id_uuid,
+				ARPCObjectRemoveReason(reason),
+			)
 
-		case "BufferGetItemsCount":
-			buffer_id, not_found, err :=
+		case "NewSocket":
+			listening_socket_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"buffer_id",
+					"listening_socket_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter buffer_id")
+				err_input =
+					errors.New("not found required parameter listening_socket_id")
 				break
 			}
 
@@ -672,28 +696,28 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
+			listening_socket_id_uuid, err := gouuidtools.NewUUIDFromString(listening_socket_id)
 			if err != nil {
 				err_input = err
 				break
 			}
 
-			result, err_processing_not_internal, err_processing_internal =
-				self.controller.BufferGetItemsCount(
-					buffer_id_uuid,
-				)
+			self.controller.NewSocket(
+				listening_socket_id_uuid,
+			)
 
-		case "BufferGetItemsIds":
-			buffer_id, not_found, err :=
+		case "SocketStreamCredit":
+			connected_socket_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"buffer_id",
+					"connected_socket_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter buffer_id")
+				err_input =
+					errors.New("not found required parameter connected_socket_id")
 				break
 			}
 
@@ -702,24 +726,22 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
+			connected_socket_id_uuid, err :=
+				gouuidtools.NewUUIDFromString(connected_socket_id)
 			if err != nil {
 				err_input = err
 				break
 			}
 
-			// 1st spec
-
-			first_spec_str, not_found, err :=
-				anyutils.TraverseObjectTree002_string(
-					msg_par,
-					true,
-					true,
-					"first_spec",
-				)
+			credit, not_found, err := anyutils.TraverseObjectTree002_int(
+				msg_par,
+				true,
+				true,
+				"credit",
+			)
 
 			if not_found {
-				err_input = errors.New("not found required parameter first_spec")
+				err_input = errors.New("not found required parameter credit")
 				break
 			}
 
@@ -728,24 +750,23 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			first_spec, ty := NewARPCBufferItemSpecifierFromString(first_spec_str)
-			if ty == ARPCBufferItemSpecifierTypeInvalid {
-				err_input = errors.New("invalid value for first_spec")
-				break
-			}
-
-			// 2nd spec
+			self.controller.SocketStreamCredit(
+				connected_socket_id_uuid,
+				credit,
+			)
 
-			last_spec_str, not_found, err :=
+		case "SocketStreamData":
+			connected_socket_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"last_spec",
+					"connected_socket_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter last_spec")
+				err_input =
+					errors.New("not found required parameter connected_socket_id")
 				break
 			}
 
@@ -754,30 +775,22 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			last_spec, ty := NewARPCBufferItemSpecifierFromString(last_spec_str)
-			if ty == ARPCBufferItemSpecifierTypeInvalid {
-				err_input = errors.New("invalid value for last_spec")
+			connected_socket_id_uuid, err :=
+				gouuidtools.NewUUIDFromString(connected_socket_id)
+			if err != nil {
+				err_input = err
 				break
 			}
 
-			result, err_processing_not_internal, err_processing_internal =
-				self.controller.BufferGetItemsIds(
-					buffer_id_uuid,
-					first_spec,
-					last_spec,
-				)
-
-		case "BufferGetItemsTimesByIds":
-			buffer_id, not_found, err :=
-				anyutils.TraverseObjectTree002_string(
-					msg_par,
-					true,
-					true,
-					"buffer_id",
-				)
+			seq, not_found, err := anyutils.TraverseObjectTree002_int(
+				msg_par,
+				true,
+				true,
+				"seq",
+			)
 
 			if not_found {
-				err_input = errors.New("not found required parameter buffer_id")
+				err_input = errors.New("not found required parameter seq")
 				break
 			}
 
@@ -786,71 +799,75 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
-			if err != nil {
-				err_input = err
-				break
+			var data []byte
+
+			if data_any, found := msg_par["data"]; found {
+				data, err = decodeARPCBinary(data_any)
+				if err != nil {
+					err_input = err
+					break
+				}
 			}
 
-			ids, not_found, err :=
-				anyutils.TraverseObjectTree002_str_list(
+			var stream_err error
+
+			stream_err_code, not_found, err :=
+				anyutils.TraverseObjectTree002_int(
 					msg_par,
 					true,
 					true,
-					"ids",
+					"err_code",
 				)
 
-			if not_found {
-				err_input = errors.New("not found required parameter ids")
-				break
-			}
-
 			if err != nil {
 				err_processing_internal = err
 				break
 			}
 
-			result, err_processing_not_internal, err_processing_internal =
-				self.controller.BufferGetItemsTimesByIds(
-					buffer_id_uuid,
-					ids,
-				)
-
-		case "BufferGetItemsByIds":
-			buffer_id, not_found, err :=
-				anyutils.TraverseObjectTree002_string(
+			if !not_found {
+				err_msg, _, err := anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"buffer_id",
+					"err_msg",
 				)
 
-			if not_found {
-				err_input = errors.New("not found required parameter buffer_id")
-				break
-			}
+				if err != nil {
+					err_processing_internal = err
+					break
+				}
 
-			if err != nil {
-				err_processing_internal = err
-				break
+				stream_err = NewARPCError(
+					ARPCErrorCode(stream_err_code),
+					err_msg,
+					nil,
+				)
 			}
 
-			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
-			if err != nil {
-				err_input = err
-				break
-			}
+			self.controller.SocketStreamData(
+				connected_socket_id_uuid,
+				seq,
+				data,
+				stream_err,
+			)
 
-			ids, not_found, err :=
-				anyutils.TraverseObjectTree002_str_list(
+		// ------------ Methods ------------
+
+		case "CallGetList":
+			result, err_processing_not_internal, err_processing_internal =
+				self.controller.CallGetList()
+
+		case "CallGetInfo":
+			call_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"ids",
+					"call_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter ids")
+				err_input = errors.New("not found required parameter call_id")
 				break
 			}
 
@@ -859,23 +876,28 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
+			call_id_uuid, err := gouuidtools.NewUUIDFromString(call_id)
+			if err != nil {
+				err_input = err
+				break
+			}
+
 			result, err_processing_not_internal, err_processing_internal =
-				self.controller.BufferGetItemsByIds(
-					buffer_id_uuid,
-					ids,
+				self.controller.CallGetInfo(
+					call_id_uuid,
 				)
 
-		case "BufferGetItemsFirstTime":
-			buffer_id, not_found, err :=
+		case "CallGetName":
+			call_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"buffer_id",
+					"call_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter buffer_id")
+				err_input = errors.New("not found required parameter call_id")
 				break
 			}
 
@@ -884,29 +906,28 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
+			call_id_uuid, err := gouuidtools.NewUUIDFromString(call_id)
 			if err != nil {
 				err_input = err
 				break
 			}
 
 			result, err_processing_not_internal, err_processing_internal =
-				self.controller.BufferGetItemsFirstTime(
-					buffer_id_uuid,
+				self.controller.CallGetName(
+					call_id_uuid,
 				)
 
-		case "BufferGetItemsLastTime":
-
-			buffer_id, not_found, err :=
+		case "CallGetArgCount":
+			call_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"buffer_id",
+					"call_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter buffer_id")
+				err_input = errors.New("not found required parameter call_id")
 				break
 			}
 
@@ -915,29 +936,27 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
+			call_id_uuid, err := gouuidtools.NewUUIDFromString(call_id)
 			if err != nil {
 				err_input = err
 				break
 			}
 
 			result, err_processing_not_internal, err_processing_internal =
-				self.controller.BufferGetItemsLastTime(
-					buffer_id_uuid,
+				self.controller.CallGetArgCount(
+					call_id_uuid,
 				)
 
-		case "BufferSubscribeOnUpdatesNotification":
-
-			buffer_id, not_found, err :=
-				anyutils.TraverseObjectTree002_string(
-					msg_par,
-					true,
-					true,
-					"buffer_id",
-				)
+		case "CallGetArgValue":
+			call_id, not_found, err := anyutils.TraverseObjectTree002_string(
+				msg_par,
+				true,
+				true,
+				"call_id",
+			)
 
 			if not_found {
-				err_input = errors.New("not found required parameter buffer_id")
+				err_input = errors.New("not found required parameter call_id")
 				break
 			}
 
@@ -946,32 +965,43 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
+			call_id_uuid, err := gouuidtools.NewUUIDFromString(call_id)
 			if err != nil {
 				err_input = err
 				break
 			}
 
-			err_processing_not_internal, err_processing_internal =
-				self.controller.BufferSubscribeOnUpdatesNotification(
-					buffer_id_uuid,
-				)
+			first, not_found, err := anyutils.TraverseObjectTree002_int(
+				msg_par,
+				true,
+				true,
+				"first",
+			)
 
-			result = err_processing_not_internal == nil &&
-				err_processing_internal == nil
+			if not_found {
+				err_input = errors.New("not found required parameter 'first'")
+				break
+			}
 
-		case "BufferUnsubscribeFromUpdatesNotification":
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
 
-			buffer_id, not_found, err :=
-				anyutils.TraverseObjectTree002_string(
-					msg_par,
-					true,
-					true,
-					"buffer_id",
-				)
+			if first < 0 {
+				err_input = errors.New("first must be >= 0")
+				break
+			}
+
+			last, not_found, err := anyutils.TraverseObjectTree002_int(
+				msg_par,
+				true,
+				true,
+				"last",
+			)
 
 			if not_found {
-				err_input = errors.New("not found required parameter buffer_id")
+				err_input = errors.New("not found required parameter 'last'")
 				break
 			}
 
@@ -980,32 +1010,28 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
-			if err != nil {
-				err_input = err
+			if last < first {
+				err_input = errors.New("last must be >= first")
 				break
 			}
 
-			err_processing_not_internal, err_processing_internal =
-				self.controller.BufferUnsubscribeFromUpdatesNotification(
-					buffer_id_uuid,
+			result, err_processing_not_internal, err_processing_internal =
+				self.controller.CallGetArgValues(
+					call_id_uuid,
+					first, last,
 				)
 
-			result = err_processing_not_internal == nil &&
-				err_processing_internal == nil
-
-		case "BufferGetIsSubscribedOnUpdatesNotification":
-
-			buffer_id, not_found, err :=
+		case "CallClose":
+			call_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"buffer_id",
+					"call_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter buffer_id")
+				err_input = errors.New("not found required parameter call_id")
 				break
 			}
 
@@ -1014,19 +1040,21 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
+			call_id_uuid, err := gouuidtools.NewUUIDFromString(call_id)
 			if err != nil {
 				err_input = err
 				break
 			}
 
-			result, err_processing_not_internal, err_processing_internal =
-				self.controller.BufferGetIsSubscribedOnUpdatesNotification(
-					buffer_id_uuid,
+			err_processing_not_internal, err_processing_internal =
+				self.controller.CallClose(
+					call_id_uuid,
 				)
 
-		case "BufferGetListSubscribedUpdatesNotifications":
+			result = err_processing_not_internal == nil &&
+				err_processing_internal == nil
 
+		case "BufferGetInfo":
 			buffer_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
@@ -1052,12 +1080,11 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 			}
 
 			result, err_processing_not_internal, err_processing_internal =
-				self.controller.BufferGetIsSubscribedOnUpdatesNotification(
+				self.controller.BufferGetInfo(
 					buffer_id_uuid,
 				)
 
-		case "BufferBinaryGetSize":
-
+		case "BufferGetItemsCount":
 			buffer_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
@@ -1083,11 +1110,11 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 			}
 
 			result, err_processing_not_internal, err_processing_internal =
-				self.controller.BufferBinaryGetSize(
+				self.controller.BufferGetItemsCount(
 					buffer_id_uuid,
 				)
 
-		case "BufferBinaryGetSlice":
+		case "BufferGetItemsIds":
 			buffer_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
@@ -1109,16 +1136,17 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
 			if err != nil {
 				err_input = err
+				break
 			}
 
 			// 1st spec
 
-			start_index, not_found, err :=
-				anyutils.TraverseObjectTree002_int(
+			first_spec_str, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"start_index",
+					"first_spec",
 				)
 
 			if not_found {
@@ -1131,14 +1159,20 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
+			first_spec, ty := NewARPCBufferItemSpecifierFromString(first_spec_str)
+			if ty == ARPCBufferItemSpecifierTypeInvalid {
+				err_input = errors.New("invalid value for first_spec")
+				break
+			}
+
 			// 2nd spec
 
-			end_index, not_found, err :=
-				anyutils.TraverseObjectTree002_int(
+			last_spec_str, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"end_index",
+					"last_spec",
 				)
 
 			if not_found {
@@ -1151,29 +1185,30 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
+			last_spec, ty := NewARPCBufferItemSpecifierFromString(last_spec_str)
+			if ty == ARPCBufferItemSpecifierTypeInvalid {
+				err_input = errors.New("invalid value for last_spec")
+				break
+			}
+
 			result, err_processing_not_internal, err_processing_internal =
-				self.controller.BufferBinaryGetSlice(
+				self.controller.BufferGetItemsIds(
 					buffer_id_uuid,
-					start_index,
-					end_index,
+					first_spec,
+					last_spec,
 				)
 
-		case "TransmissionGetList":
-			result, err_processing_not_internal, err_processing_internal =
-				self.controller.TransmissionGetList()
-
-		case "TransmissionGetInfo":
-			transmission_id, not_found, err :=
+		case "BufferGetItemsTimesByIds":
+			buffer_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"transmission_id",
+					"buffer_id",
 				)
 
 			if not_found {
-				err_input =
-					errors.New("not found required parameter transmission_id")
+				err_input = errors.New("not found required parameter buffer_id")
 				break
 			}
 
@@ -1182,33 +1217,22 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			transmission_id_uuid, err :=
-				gouuidtools.NewUUIDFromString(transmission_id)
+			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
 			if err != nil {
 				err_input = err
+				break
 			}
 
-			result, err_processing_not_internal, err_processing_internal =
-				self.controller.TransmissionGetInfo(
-					transmission_id_uuid,
-				)
-
-		case "SocketGetList":
-			result, err_processing_not_internal, err_processing_internal =
-				self.controller.SocketGetList()
-
-		case "SocketOpen":
-			listening_socket_id, not_found, err :=
-				anyutils.TraverseObjectTree002_string(
+			ids, not_found, err :=
+				anyutils.TraverseObjectTree002_str_list(
 					msg_par,
 					true,
 					true,
-					"listening_socket_id",
+					"ids",
 				)
 
 			if not_found {
-				err_input =
-					errors.New("not found required parameter listening_socket_id")
+				err_input = errors.New("not found required parameter ids")
 				break
 			}
 
@@ -1217,29 +1241,23 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			listening_socket_id_uuid, err :=
-				gouuidtools.NewUUIDFromString(listening_socket_id)
-			if err != nil {
-				err_input = err
-			}
-
 			result, err_processing_not_internal, err_processing_internal =
-				self.controller.SocketOpen(
-					listening_socket_id_uuid,
+				self.controller.BufferGetItemsTimesByIds(
+					buffer_id_uuid,
+					ids,
 				)
 
-		case "SocketRead":
-			connected_socket_id, not_found, err :=
+		case "BufferGetItemsByIds":
+			buffer_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"connected_socket_id",
+					"buffer_id",
 				)
 
 			if not_found {
-				err_input =
-					errors.New("not found required parameter connected_socket_id")
+				err_input = errors.New("not found required parameter buffer_id")
 				break
 			}
 
@@ -1248,22 +1266,22 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			connected_socket_id_uuid, err :=
-				gouuidtools.NewUUIDFromString(connected_socket_id)
+			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
 			if err != nil {
 				err_input = err
+				break
 			}
 
-			try_read_size, not_found, err := anyutils.TraverseObjectTree002_int(
-				msg_par,
-				true,
-				true,
-				"try_read_size",
-			)
+			ids, not_found, err :=
+				anyutils.TraverseObjectTree002_str_list(
+					msg_par,
+					true,
+					true,
+					"ids",
+				)
 
 			if not_found {
-				err_input =
-					errors.New("not found required parameter try_read_size")
+				err_input = errors.New("not found required parameter ids")
 				break
 			}
 
@@ -1272,30 +1290,27 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			if try_read_size < 0 {
-				err_input = errors.New("try_read_size must be >= 0")
-				break
-			}
+			var items []*ARPCBufferItem
 
-			result, err_processing_not_internal, err_processing_internal =
-				self.controller.SocketRead(
-					connected_socket_id_uuid,
-					try_read_size,
+			items, err_processing_not_internal, err_processing_internal =
+				self.controller.BufferGetItemsByIds(
+					buffer_id_uuid,
+					ids,
 				)
 
-		case "SocketWrite":
+			result = bufferItemsEncodeBinary(items)
 
-			connected_socket_id, not_found, err :=
+		case "BufferGetItemsFirstTime":
+			buffer_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"connected_socket_id",
+					"buffer_id",
 				)
 
 			if not_found {
-				err_input =
-					errors.New("not found required parameter connected_socket_id")
+				err_input = errors.New("not found required parameter buffer_id")
 				break
 			}
 
@@ -1304,21 +1319,29 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			connected_socket_id_uuid, err :=
-				gouuidtools.NewUUIDFromString(connected_socket_id)
+			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
 			if err != nil {
 				err_input = err
+				break
 			}
 
-			b, not_found, err := anyutils.TraverseObjectTree002_byte_list(
-				msg_par,
-				true,
-				true,
-				"b",
-			)
+			result, err_processing_not_internal, err_processing_internal =
+				self.controller.BufferGetItemsFirstTime(
+					buffer_id_uuid,
+				)
+
+		case "BufferGetItemsLastTime":
+
+			buffer_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"buffer_id",
+				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter b")
+				err_input = errors.New("not found required parameter buffer_id")
 				break
 			}
 
@@ -1327,23 +1350,29 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
+			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
+			if err != nil {
+				err_input = err
+				break
+			}
+
 			result, err_processing_not_internal, err_processing_internal =
-				self.controller.SocketWrite(
-					connected_socket_id_uuid,
-					b,
+				self.controller.BufferGetItemsLastTime(
+					buffer_id_uuid,
 				)
 
-		case "SocketClose":
-			connected_socket_id, not_found, err :=
+		case "BufferSubscribeOnUpdatesNotification":
+
+			buffer_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"connected_socket_id",
+					"buffer_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter connected_socket_id")
+				err_input = errors.New("not found required parameter buffer_id")
 				break
 			}
 
@@ -1352,34 +1381,32 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			connected_socket_id_uuid, err :=
-				gouuidtools.NewUUIDFromString(connected_socket_id)
+			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
 			if err != nil {
 				err_input = err
+				break
 			}
 
 			err_processing_not_internal, err_processing_internal =
-				self.controller.SocketClose(
-					connected_socket_id_uuid,
+				self.controller.BufferSubscribeOnUpdatesNotification(
+					buffer_id_uuid,
 				)
 
 			result = err_processing_not_internal == nil &&
 				err_processing_internal == nil
 
-		case "SocketSetDeadline":
-			connected_socket_id, not_found, err :=
+		case "BufferUnsubscribeFromUpdatesNotification":
+
+			buffer_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"connected_socket_id",
+					"buffer_id",
 				)
 
 			if not_found {
-				err_input =
-					errors.New(
-						"not found required parameter connected_socket_id",
-					)
+				err_input = errors.New("not found required parameter buffer_id")
 				break
 			}
 
@@ -1388,22 +1415,32 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			connected_socket_id_uuid, err :=
-				gouuidtools.NewUUIDFromString(connected_socket_id)
+			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
 			if err != nil {
 				err_input = err
+				break
 			}
 
-			t_str, not_found, err :=
+			err_processing_not_internal, err_processing_internal =
+				self.controller.BufferUnsubscribeFromUpdatesNotification(
+					buffer_id_uuid,
+				)
+
+			result = err_processing_not_internal == nil &&
+				err_processing_internal == nil
+
+		case "BufferGetIsSubscribedOnUpdatesNotification":
+
+			buffer_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"t",
+					"buffer_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter t")
+				err_input = errors.New("not found required parameter buffer_id")
 				break
 			}
 
@@ -1412,32 +1449,33 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			t, err := time.Parse(time.RFC3339Nano, t_str)
+			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
 			if err != nil {
-				err_processing_internal = err
+				err_input = err
 				break
 			}
 
-			err_processing_not_internal, err_processing_internal =
-				self.controller.SocketSetDeadline(
-					connected_socket_id_uuid,
-					t,
+			result, err_processing_not_internal, err_processing_internal =
+				self.controller.BufferGetIsSubscribedOnUpdatesNotification(
+					buffer_id_uuid,
 				)
 
-			result = err_processing_not_internal == nil &&
-				err_processing_internal == nil
+		case "BufferGetListSubscribedUpdatesNotifications":
+			result, err_processing_not_internal, err_processing_internal =
+				self.controller.BufferGetListSubscribedUpdatesNotifications()
 
-		case "SocketSetReadDeadline":
-			connected_socket_id, not_found, err :=
+		case "BufferBinaryGetSize":
+
+			buffer_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"connected_socket_id",
+					"buffer_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter connected_socket_id")
+				err_input = errors.New("not found required parameter buffer_id")
 				break
 			}
 
@@ -1446,22 +1484,28 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			connected_socket_id_uuid, err :=
-				gouuidtools.NewUUIDFromString(connected_socket_id)
+			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
 			if err != nil {
 				err_input = err
+				break
 			}
 
-			t_str, not_found, err :=
+			result, err_processing_not_internal, err_processing_internal =
+				self.controller.BufferBinaryGetSize(
+					buffer_id_uuid,
+				)
+
+		case "BufferBinaryGetSlice":
+			buffer_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"t",
+					"buffer_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter t")
+				err_input = errors.New("not found required parameter buffer_id")
 				break
 			}
 
@@ -1470,32 +1514,40 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			t, err := time.Parse(time.RFC3339Nano, t_str)
+			buffer_id_uuid, err := gouuidtools.NewUUIDFromString(buffer_id)
 			if err != nil {
-				err_processing_internal = err
+				err_input = err
 				break
 			}
 
-			err_processing_not_internal, err_processing_internal =
-				self.controller.SocketSetReadDeadline(
-					connected_socket_id_uuid,
-					t,
+			start_index, not_found, err :=
+				anyutils.TraverseObjectTree002_int(
+					msg_par,
+					true,
+					true,
+					"start_index",
 				)
 
-			result = err_processing_not_internal == nil &&
-				err_processing_internal == nil
+			if not_found {
+				err_input = errors.New("not found required parameter start_index")
+				break
+			}
 
-		case "SocketSetWriteDeadline":
-			connected_socket_id, not_found, err :=
-				anyutils.TraverseObjectTree002_string(
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			end_index, not_found, err :=
+				anyutils.TraverseObjectTree002_int(
 					msg_par,
 					true,
 					true,
-					"connected_socket_id",
+					"end_index",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter connected_socket_id")
+				err_input = errors.New("not found required parameter end_index")
 				break
 			}
 
@@ -1504,22 +1556,33 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			connected_socket_id_uuid, err :=
-				gouuidtools.NewUUIDFromString(connected_socket_id)
-			if err != nil {
-				err_input = err
-			}
+			var data []byte
 
-			t_str, not_found, err :=
+			data, err_processing_not_internal, err_processing_internal =
+				self.controller.BufferBinaryGetSlice(
+					buffer_id_uuid,
+					start_index,
+					end_index,
+				)
+
+			result = encodeARPCBinary(data)
+
+		case "TransmissionGetList":
+			result, err_processing_not_internal, err_processing_internal =
+				self.controller.TransmissionGetList()
+
+		case "TransmissionGetInfo":
+			transmission_id, not_found, err :=
 				anyutils.TraverseObjectTree002_string(
 					msg_par,
 					true,
 					true,
-					"t",
+					"transmission_id",
 				)
 
 			if not_found {
-				err_input = errors.New("not found required parameter t")
+				err_input =
+					errors.New("not found required parameter transmission_id")
 				break
 			}
 
@@ -1528,25 +1591,524 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 				break
 			}
 
-			t, err := time.Parse(time.RFC3339Nano, t_str)
+			transmission_id_uuid, err :=
+				gouuidtools.NewUUIDFromString(transmission_id)
 			if err != nil {
-				err_processing_internal = err
+				err_input = err
 				break
 			}
 
-			err_processing_not_internal, err_processing_internal =
-				self.controller.SocketSetWriteDeadline(
-					connected_socket_id_uuid,
-					t,
+			result, err_processing_not_internal, err_processing_internal =
+				self.controller.TransmissionGetInfo(
+					transmission_id_uuid,
 				)
 
-			result = err_processing_not_internal == nil &&
-				err_processing_internal == nil
-		}
+		case "SocketGetList":
+			result, err_processing_not_internal, err_processing_internal =
+				self.controller.SocketGetList()
 
-		if msg_has_id {
-			err_processing_internal = self.methodReplyAction(
-				msg_id,
+		case "SocketOpen":
+			listening_socket_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"listening_socket_id",
+				)
+
+			if not_found {
+				err_input =
+					errors.New("not found required parameter listening_socket_id")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			listening_socket_id_uuid, err :=
+				gouuidtools.NewUUIDFromString(listening_socket_id)
+			if err != nil {
+				err_input = err
+				break
+			}
+
+			// opening may take a while, so reply is sent asynchronously
+			go func(controller ARPCNodeCtlI) {
+				result, err_processing_not_internal, err_processing_internal :=
+					controller.SocketOpen(
+						listening_socket_id_uuid,
+					)
+				if msg_has_id {
+					self.asyncHandlerResult(
+						nil,
+						self.methodReplyAction(
+							msg_id,
+							result,
+							err_code,
+							nil,
+							err_processing_not_internal,
+							err_processing_internal,
+						),
+					)
+				}
+			}(self.controller)
+
+			return nil, nil
+
+		case "SocketRead":
+			connected_socket_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"connected_socket_id",
+				)
+
+			if not_found {
+				err_input =
+					errors.New("not found required parameter connected_socket_id")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			connected_socket_id_uuid, err :=
+				gouuidtools.NewUUIDFromString(connected_socket_id)
+			if err != nil {
+				err_input = err
+				break
+			}
+
+			try_read_size, not_found, err := anyutils.TraverseObjectTree002_int(
+				msg_par,
+				true,
+				true,
+				"try_read_size",
+			)
+
+			if not_found {
+				err_input =
+					errors.New("not found required parameter try_read_size")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			if try_read_size < 0 {
+				err_input = errors.New("try_read_size must be >= 0")
+				break
+			}
+
+			// reading blocks until data available, so reply is sent
+			// asynchronously, not to block handling of other messages
+			go func(controller ARPCNodeCtlI) {
+				data, err_processing_not_internal, err_processing_internal :=
+					controller.SocketRead(
+						connected_socket_id_uuid,
+						try_read_size,
+					)
+				if msg_has_id {
+					self.asyncHandlerResult(
+						nil,
+						self.methodReplyAction(
+							msg_id,
+							encodeARPCBinary(data),
+							err_code,
+							nil,
+							err_processing_not_internal,
+							err_processing_internal,
+						),
+					)
+				}
+			}(self.controller)
+
+			return nil, nil
+
+		case "SocketWrite":
+
+			connected_socket_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"connected_socket_id",
+				)
+
+			if not_found {
+				err_input =
+					errors.New("not found required parameter connected_socket_id")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			connected_socket_id_uuid, err :=
+				gouuidtools.NewUUIDFromString(connected_socket_id)
+			if err != nil {
+				err_input = err
+				break
+			}
+
+			b_any, found := msg_par["b"]
+			if !found {
+				err_input = errors.New("not found required parameter b")
+				break
+			}
+
+			b, err := decodeARPCBinary(b_any)
+			if err != nil {
+				err_input = err
+				break
+			}
+
+			result, err_processing_not_internal, err_processing_internal =
+				self.controller.SocketWrite(
+					connected_socket_id_uuid,
+					b,
+				)
+
+		case "SocketClose":
+			connected_socket_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"connected_socket_id",
+				)
+
+			if not_found {
+				err_input = errors.New("not found required parameter connected_socket_id")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			connected_socket_id_uuid, err :=
+				gouuidtools.NewUUIDFromString(connected_socket_id)
+			if err != nil {
+				err_input = err
+				break
+			}
+
+			err_processing_not_internal, err_processing_internal =
+				self.controller.SocketClose(
+					connected_socket_id_uuid,
+				)
+
+			result = err_processing_not_internal == nil &&
+				err_processing_internal == nil
+
+		case "SocketSetDeadline":
+			connected_socket_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"connected_socket_id",
+				)
+
+			if not_found {
+				err_input =
+					errors.New(
+						"not found required parameter connected_socket_id",
+					)
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			connected_socket_id_uuid, err :=
+				gouuidtools.NewUUIDFromString(connected_socket_id)
+			if err != nil {
+				err_input = err
+				break
+			}
+
+			t_str, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"t",
+				)
+
+			if not_found {
+				err_input = errors.New("not found required parameter t")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			t, err := time.Parse(time.RFC3339Nano, t_str)
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			err_processing_not_internal, err_processing_internal =
+				self.controller.SocketSetDeadline(
+					connected_socket_id_uuid,
+					t,
+				)
+
+			result = err_processing_not_internal == nil &&
+				err_processing_internal == nil
+
+		case "SocketSetReadDeadline":
+			connected_socket_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"connected_socket_id",
+				)
+
+			if not_found {
+				err_input = errors.New("not found required parameter connected_socket_id")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			connected_socket_id_uuid, err :=
+				gouuidtools.NewUUIDFromString(connected_socket_id)
+			if err != nil {
+				err_input = err
+				break
+			}
+
+			t_str, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"t",
+				)
+
+			if not_found {
+				err_input = errors.New("not found required parameter t")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			t, err := time.Parse(time.RFC3339Nano, t_str)
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			err_processing_not_internal, err_processing_internal =
+				self.controller.SocketSetReadDeadline(
+					connected_socket_id_uuid,
+					t,
+				)
+
+			result = err_processing_not_internal == nil &&
+				err_processing_internal == nil
+
+		case "SocketSetWriteDeadline":
+			connected_socket_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"connected_socket_id",
+				)
+
+			if not_found {
+				err_input = errors.New("not found required parameter connected_socket_id")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			connected_socket_id_uuid, err :=
+				gouuidtools.NewUUIDFromString(connected_socket_id)
+			if err != nil {
+				err_input = err
+				break
+			}
+
+			t_str, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"t",
+				)
+
+			if not_found {
+				err_input = errors.New("not found required parameter t")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			t, err := time.Parse(time.RFC3339Nano, t_str)
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			err_processing_not_internal, err_processing_internal =
+				self.controller.SocketSetWriteDeadline(
+					connected_socket_id_uuid,
+					t,
+				)
+
+			result = err_processing_not_internal == nil &&
+				err_processing_internal == nil
+
+		case "SocketStreamStart":
+			connected_socket_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"connected_socket_id",
+				)
+
+			if not_found {
+				err_input =
+					errors.New("not found required parameter connected_socket_id")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			connected_socket_id_uuid, err :=
+				gouuidtools.NewUUIDFromString(connected_socket_id)
+			if err != nil {
+				err_input = err
+				break
+			}
+
+			window, not_found, err := anyutils.TraverseObjectTree002_int(
+				msg_par,
+				true,
+				true,
+				"window",
+			)
+
+			if not_found {
+				err_input = errors.New("not found required parameter window")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			if window <= 0 {
+				err_input = errors.New("window must be > 0")
+				break
+			}
+
+			err_processing_not_internal, err_processing_internal =
+				self.controller.SocketStreamStart(
+					connected_socket_id_uuid,
+					window,
+				)
+
+			result = err_processing_not_internal == nil &&
+				err_processing_internal == nil
+
+		case "Touch":
+			object_type, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"object_type",
+				)
+
+			if not_found {
+				err_input =
+					errors.New("not found required parameter object_type")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			object_id, not_found, err :=
+				anyutils.TraverseObjectTree002_string(
+					msg_par,
+					true,
+					true,
+					"object_id",
+				)
+
+			if not_found {
+				err_input =
+					errors.New("not found required parameter object_id")
+				break
+			}
+
+			if err != nil {
+				err_processing_internal = err
+				break
+			}
+
+			object_id_uuid, err := gouuidtools.NewUUIDFromString(object_id)
+			if err != nil {
+				err_input = err
+				break
+			}
+
+			var ttl time.Duration
+
+			ttl, err_processing_not_internal, err_processing_internal =
+				self.controller.Touch(
+					ARPCObjectType(object_type),
+					object_id_uuid,
+				)
+
+			result = ttl.Milliseconds()
+		}
+
+		if msg_has_id {
+			err_processing_internal = self.methodReplyAction(
+				msg_id,
 				result,
 				err_code,
 				err_input,
@@ -1564,9 +2126,24 @@ func (self *ARPCNode) handleJRPCNodeMessage(
 	return errors.New("invalid message format"), errors.New("input error")
 }
 
-// note: err_code used only if err_reply and/or err != nil.
-// maybe it should be generated by methodReplyAction itself and shouldn't be
-// provided by caller
+func (self *ARPCNode) asyncHandlerResult(err_proto error, err error) {
+	if err_proto == nil && err == nil {
+		return
+	}
+
+	if self.debug {
+		self.DebugPrintln("async handler errors:", err_proto, err)
+	}
+
+	if self.OnAsyncHandlerErrorsCB != nil {
+		self.OnAsyncHandlerErrorsCB(err_proto, err)
+	}
+}
+
+// note: err_code used only for err_input, which isn't ARPCError.
+// err_processing_not_internal is sent with it's ARPCError code
+// (ARPCErrorCodeGeneric if it's not ARPCError).
+// err_processing_internal details are not sent to remote side
 func (self *ARPCNode) methodReplyAction(
 	msg_id any,
 	result any,
@@ -1591,166 +2168,537 @@ func (self *ARPCNode) methodReplyAction(
 
 	msg.SetId(msg_id)
 
-	// err_code = 10 // todo: find better value
+	var err error
+
+	if err_processing_internal != nil {
+		e := &gojsonrpc2.JSONRPC2Error{
+			Code:    int(gojsonrpc2.ProtocolErrorInternalError),
+			Message: "internal server error",
+		}
+		msg.Error = e
+		// note: intentionaly ignoring error from SendError()
+		err = self.jrpc_node.SendError(msg)
+		if err != nil {
+			return err
+		}
+
+		return err_processing_internal
+	}
+
+	if err_processing_not_internal != nil {
+		// ARPCErrors are passed as is, others become ARPCErrorCodeGeneric
+		msg.Error = ToARPCError(err_processing_not_internal).genJSONRPC2Error()
+		return self.jrpc_node.SendError(msg)
+	}
+
+	if err_input != nil {
+		var arpc_err *ARPCError
+		if errors.As(err_input, &arpc_err) {
+			msg.Error = arpc_err.genJSONRPC2Error()
+			return self.jrpc_node.SendError(msg)
+		}
+
+		if err_code != int(gojsonrpc2.ProtocolErrorMethodNotFound) {
+			err_code = int(gojsonrpc2.ProtocolErrorInvalidParams)
+		}
+		e := &gojsonrpc2.JSONRPC2Error{
+			Code:    err_code,
+			Message: err_input.Error(),
+		}
+		msg.Error = e
+		return self.jrpc_node.SendError(msg)
+	}
+
+	msg.Result = result
+	err = self.jrpc_node.SendResponse(msg)
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ----------------------------------------
+// notifications
+// ----------------------------------------
+
+func (self *ARPCNode) NewCall(
+	call_id *gouuidtools.UUID,
+	response_on *gouuidtools.UUID,
+) error {
+	if self.IsClosed() {
+		return ErrARPCClosed
+	}
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "NewCall"
+
+	params := map[string]any{"call_id": call_id.Format()}
+
+	if response_on != nil && !response_on.IsNil() {
+		params["response_on"] = response_on.Format()
+	}
+
+	msg.Params = params
+
+	return self.jrpc_node.SendNotification(msg)
+}
+
+func (self *ARPCNode) NewBuffer(
+	buffer_id *gouuidtools.UUID,
+) error {
+	if self.IsClosed() {
+		return ErrARPCClosed
+	}
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "NewBuffer"
+
+	msg.Params = map[string]any{"buffer_id": buffer_id.Format()}
+
+	return self.jrpc_node.SendNotification(msg)
+}
+
+func (self *ARPCNode) BufferUpdated(
+	buffer_id *gouuidtools.UUID,
+) error {
+	if self.IsClosed() {
+		return ErrARPCClosed
+	}
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferUpdated"
+
+	msg.Params = map[string]any{"buffer_id": buffer_id.Format()}
+
+	return self.jrpc_node.SendNotification(msg)
+}
+
+func (self *ARPCNode) NewTransmission(
+	tarnsmission_id *gouuidtools.UUID,
+) error {
+	if self.IsClosed() {
+		return ErrARPCClosed
+	}
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "NewTransmission"
+
+	msg.Params = map[string]any{
+		"tarnsmission_id": tarnsmission_id.Format(),
+	}
+
+	return self.jrpc_node.SendNotification(msg)
+}
+
+// inform remote node what object (buffer or transmission) is finished
+// and won't change anymore
+func (self *ARPCNode) ObjectFinished(
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+) error {
+	if self.IsClosed() {
+		return ErrARPCClosed
+	}
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "ObjectFinished"
+
+	msg.Params = map[string]any{
+		"object_type": string(object_type),
+		"object_id":   object_id.Format(),
+	}
+
+	return self.jrpc_node.SendNotification(msg)
+}
+
+// inform remote node what object is deleted and can't be accessed anymore
+func (self *ARPCNode) ObjectRemoved(
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+	reason ARPCObjectRemoveReason,
+) error {
+	if self.IsClosed() {
+		return ErrARPCClosed
+	}
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "ObjectRemoved"
+
+	msg.Params = map[string]any{
+		"object_type": string(object_type),
+		"object_id":   object_id.Format(),
+		"reason":      string(reason),
+	}
+
+	return self.jrpc_node.SendNotification(msg)
+}
+
+// data chunk of connected socket, which is streamed to remote node (see
+// SocketStreamStart). seq is number of chunk, starting from 0.
+// stream_err is sent with last chunk, if socket reading failed
+func (self *ARPCNode) SocketStreamData(
+	connected_socket_id *gouuidtools.UUID,
+	seq int,
+	data []byte,
+	stream_err error,
+) error {
+	if self.IsClosed() {
+		return ErrARPCClosed
+	}
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "SocketStreamData"
+
+	params := map[string]any{
+		"connected_socket_id": connected_socket_id.Format(),
+		"seq":                 seq,
+	}
+
+	if len(data) != 0 {
+		params["data"] = encodeARPCBinary(data)
+	}
+
+	if stream_err != nil {
+		e := ToARPCError(stream_err)
+		params["err_code"] = int(e.Code)
+		params["err_msg"] = e.Message
+	}
+
+	msg.Params = params
+
+	return self.jrpc_node.SendNotification(msg)
+}
+
+// inform remote node, which streams connected socket, what credit bytes
+// are consumed and it can send more
+func (self *ARPCNode) SocketStreamCredit(
+	connected_socket_id *gouuidtools.UUID,
+	credit int,
+) error {
+	if self.IsClosed() {
+		return ErrARPCClosed
+	}
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "SocketStreamCredit"
+
+	msg.Params = map[string]any{
+		"connected_socket_id": connected_socket_id.Format(),
+		"credit":              credit,
+	}
+
+	return self.jrpc_node.SendNotification(msg)
+}
+
+func (self *ARPCNode) NewSocket(
+	listening_socket_id *gouuidtools.UUID,
+) error {
+	if self.IsClosed() {
+		return ErrARPCClosed
+	}
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "NewSocket"
+
+	msg.Params = map[string]any{
+		"listening_socket_id": listening_socket_id.Format(),
+	}
+
+	return self.jrpc_node.SendNotification(msg)
+}
+
+// ----------------------------------------
+// Basic Calls
+// ----------------------------------------
+
+func (self *ARPCNode) subResultGetter01(
+	ctx context.Context,
+	req *xARPCNodePendingRequest,
+) (result any, timedout bool, closed bool,
+	result_err error, err error) {
+
+	select {
+	case <-ctx.Done():
+		return nil, false, false, nil, ctx.Err()
+	case <-req.timedout_sig:
+		return nil, true, false, nil, nil
+	case <-req.closed_sig:
+		return nil, false, true, nil, nil
+	case res := <-req.res_sig:
+		if res.err != nil {
+			return nil,
+				false, false, arpcErrorFromJSONRPC2Error(res.err), nil
+		}
+
+		return res.result, false, false, nil, nil
+	}
+}
+
+// prefix of ids of requests, sent by sendRequest()
+const ARPC_REQUEST_ID_PREFIX = "arpc-req:"
+
+// request, awaited by one of client functions.
+// cancel() deregisters it: late response is dropped and it's timeout
+// timer is stopped
+type xARPCNodePendingRequest struct {
+	node *ARPCNode
+	id   string
+
+	mtx   sync.Mutex
+	done  bool
+	timer *time.Timer
+
+	timedout_sig chan struct{}
+	closed_sig   chan struct{}
+	res_sig      chan *xARPCNodeResult
+}
+
+type xARPCNodeResult struct {
+	result any
+	err    *gojsonrpc2.JSONRPC2Error
+}
+
+// response to request, sent by sendRequest()
+type xARPCNodeResponse struct {
+	Id     any                       `json:"id"`
+	Method string                    `json:"method"`
+	Result json.RawMessage           `json:"result"`
+	Error  *gojsonrpc2.JSONRPC2Error `json:"error"`
+}
+
+func (self *ARPCNode) newPendingRequest() *xARPCNodePendingRequest {
+	req := &xARPCNodePendingRequest{
+		node:         self,
+		timedout_sig: make(chan struct{}),
+		closed_sig:   make(chan struct{}),
+		res_sig:      make(chan *xARPCNodeResult, 1),
+	}
+
+	self.pending_mtx.Lock()
+	self.next_request_id++
+	req.id = ARPC_REQUEST_ID_PREFIX +
+		strconv.FormatUint(self.next_request_id, 10)
+	self.pending[req.id] = req
+	self.pending_mtx.Unlock()
+
+	return req
+}
+
+// sends request with id of req. response is routed to req by
+// routeResponse(), so gojsonrpc2 keeps no handler for it.
+// response_timeout 0 - no timeout. if node is closed, req is closed
+// and nil is returned, so caller gets closed result from req
+func (self *ARPCNode) sendRequest(
+	msg *gojsonrpc2.Message,
+	req *xARPCNodePendingRequest,
+	response_timeout time.Duration,
+) error {
+	// req is already in pending, so Close() either is seen here or
+	// closes req itself
+	if self.IsClosed() {
+		req.closed()
+		return nil
+	}
 
-	var err error
+	msg.SetId(req.id)
 
-	if err_input != nil {
+	if response_timeout > 0 {
+		req.mtx.Lock()
+		if !req.done {
+			req.timer = time.AfterFunc(response_timeout, req.timedOut)
+		}
+		req.mtx.Unlock()
+	}
 
+	err := self.jrpc_node.SendMessage(msg)
+	if err != nil && self.IsClosed() {
+		req.closed()
+		return nil
 	}
 
-	if err_processing_internal != nil {
-		e := &gojsonrpc2.JSONRPC2Error{
-			Code:    int(gojsonrpc2.ProtocolErrorInternalError),
-			Message: "internal server error",
-		}
-		msg.Error = e
-		// note: intentionaly ignoring error from SendError()
-		err = self.jrpc_node.SendError(msg)
-		if err != nil {
-			return err
-		}
+	return err
+}
 
-		return err_processing_internal
-	}
+// passes response to it's pending request. routed is false, if data
+// isn't response to request of sendRequest(). responses to cancelled
+// requests are dropped
+func (self *ARPCNode) routeResponse(data []byte) (
+	routed bool,
+	err_proto error,
+	err error,
+) {
+	res := new(xARPCNodeResponse)
 
-	if err_processing_not_internal != nil {
-		e := &gojsonrpc2.JSONRPC2Error{
-			Code:    err_code,
-			Message: err_processing_not_internal.Error(),
-		}
-		msg.Error = e
-		return self.jrpc_node.SendError(msg)
+	if json.Unmarshal(data, res) != nil || res.Method != "" {
+		return false, nil, nil
 	}
 
-	if err_input != nil {
-		if err_code != int(gojsonrpc2.ProtocolErrorMethodNotFound) {
-			err_code = int(gojsonrpc2.ProtocolErrorInvalidParams)
-		}
-		e := &gojsonrpc2.JSONRPC2Error{
-			Code:    err_code,
-			Message: err_input.Error(),
-		}
-		msg.Error = e
-		return self.jrpc_node.SendError(msg)
+	id, ok := res.Id.(string)
+	if !ok || !strings.HasPrefix(id, ARPC_REQUEST_ID_PREFIX) {
+		return false, nil, nil
 	}
 
-	msg.Result = result
-	err = self.jrpc_node.SendResponse(msg)
-	if err != nil {
-		return err
+	if len(res.Result) == 0 && res.Error == nil {
+		return true, errors.New("response without result and error"),
+			errors.New("protocol error")
 	}
 
-	return nil
-}
-
-// ----------------------------------------
-// notifications
-// ----------------------------------------
+	self.pending_mtx.Lock()
+	req := self.pending[id]
+	self.pending_mtx.Unlock()
 
-func (self *ARPCNode) NewCall(
-	call_id *gouuidtools.UUID,
-	response_on *gouuidtools.UUID,
-) error {
-	self.nodeInvalidStateException()
-	msg := new(gojsonrpc2.Message)
-	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "NewCall"
+	if req == nil {
+		return true, nil, nil
+	}
 
-	params := map[string]any{"call_id": call_id.Format()}
+	ret := &xARPCNodeResult{err: res.Error}
 
-	if response_on != nil && !response_on.IsNil() {
-		params["response_on"] = response_on
+	if res.Error == nil {
+		err = json.Unmarshal(res.Result, &ret.result)
+		if err != nil {
+			req.closed()
+			return true, err, errors.New("protocol error")
+		}
 	}
 
-	msg.Params = params
+	req.respond(ret)
 
-	return self.jrpc_node.SendNotification(msg)
+	return true, nil, nil
 }
 
-// func (self *ARPCNode) NewBuffer(
-// 	buffer_id *gouuidtools.UUID,
-// ) error {
-// 	msg := new(gojsonrpc2.Message)
-// 	msg.Method =ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "NewBuffer"
+// false if request already finished or cancelled
+func (self *xARPCNodePendingRequest) finish() bool {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
 
-// 	msg.Params = map[string]any{"buffer_id": buffer_id.Format()}
+	if self.done {
+		return false
+	}
+	self.done = true
 
-// 	return self.jrpc_node.SendNotification(msg)
-// }
+	if self.timer != nil {
+		self.timer.Stop()
+	}
 
-func (self *ARPCNode) BufferUpdated(
-	buffer_id *gouuidtools.UUID,
-) error {
-	self.nodeInvalidStateException()
-	msg := new(gojsonrpc2.Message)
-	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferUpdated"
+	self.node.pending_mtx.Lock()
+	delete(self.node.pending, self.id)
+	self.node.pending_mtx.Unlock()
 
-	msg.Params = map[string]any{"buffer_id": buffer_id.Format()}
+	return true
+}
 
-	return self.jrpc_node.SendNotification(msg)
+func (self *xARPCNodePendingRequest) timedOut() {
+	if self.finish() {
+		close(self.timedout_sig)
+	}
 }
 
-func (self *ARPCNode) NewTransmission(
-	tarnsmission_id *gouuidtools.UUID,
-) error {
-	self.nodeInvalidStateException()
-	msg := new(gojsonrpc2.Message)
-	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "NewTransmission"
+func (self *xARPCNodePendingRequest) closed() {
+	if self.finish() {
+		close(self.closed_sig)
+	}
+}
 
-	msg.Params = map[string]any{
-		"tarnsmission_id": tarnsmission_id.Format(),
+func (self *xARPCNodePendingRequest) respond(res *xARPCNodeResult) {
+	if self.finish() {
+		self.res_sig <- res
 	}
+}
 
-	return self.jrpc_node.SendNotification(msg)
+func (self *xARPCNodePendingRequest) cancel() {
+	self.finish()
 }
 
-// func (self *ARPCNode) NewSocket(
-// 	listening_socket_id *gouuidtools.UUID,
-// ) error {
-// 	msg := new(gojsonrpc2.Message)
-// 	msg.Method =ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "NewSocket"
+// count of requests, still waiting for responses
+func (self *ARPCNode) GetPendingRequestsCount() int {
+	self.pending_mtx.Lock()
+	defer self.pending_mtx.Unlock()
+	return len(self.pending)
+}
 
-// 	msg.Params = map[string]any{
-// 		"listening_socket_id": listening_socket_id.Format(),
-// 	}
+// timeout of request, not longer than time left until ctx deadline
+func requestTimeout(
+	ctx context.Context,
+	response_timeout time.Duration,
+) time.Duration {
+	deadline, ok := ctx.Deadline()
+	if !ok {
+		return response_timeout
+	}
 
-// 	return self.jrpc_node.SendNotification(msg)
-// }
+	left := time.Until(deadline)
+	if left <= 0 {
+		// zero may mean "no timeout", so minimal possible instead
+		left = time.Nanosecond
+	}
 
-// ----------------------------------------
-// Basic Calls
-// ----------------------------------------
+	if response_timeout <= 0 || left < response_timeout {
+		return left
+	}
 
-func (self *ARPCNode) subResultGetter01(
-	timedout_sig <-chan struct{},
-	closed_sig <-chan struct{},
-	msg_sig <-chan *gojsonrpc2.Message,
-) (result any, timedout bool, closed bool,
-	result_err error, err error) {
+	return response_timeout
+}
 
-	select {
-	case <-timedout_sig:
-		return nil, true, false, nil, nil
-	case <-closed_sig:
-		return nil, false, true, nil, nil
-	case res_msg := <-msg_sig:
-		if res_msg.IsInvalid() {
-			return nil, false, false,
-				errors.New("invalid message"), errors.New("protocol error")
-		}
-		if !res_msg.HasResponseFields() {
-			return nil,
-				false, false, nil, errors.New("not a response")
+func (self *ARPCNode) ctxResponseTimeout() time.Duration {
+	if self.CtxResponseTimeout <= 0 {
+		return time.Minute
+	}
+	return self.CtxResponseTimeout
+}
+
+// numbers may come as float64 (or something else), depending on how
+// message was decoded
+func anyToInt(value any) (int, bool) {
+	switch x := value.(type) {
+	case int:
+		return x, true
+	case int64:
+		return int(x), true
+	case float64:
+		if x != float64(int(x)) {
+			return 0, false
 		}
-		if res_msg.IsError() {
-			return nil,
-				false, false, errors.New(res_msg.Error.Message), nil
+		return int(x), true
+	case json.Number:
+		r, err := x.Int64()
+		if err != nil {
+			return 0, false
 		}
+		return int(r), true
+	}
+	return 0, false
+}
 
-		return res_msg.Result,
-			false, false, nil, errors.New("result must be uuid string")
+// mapstructure.Decode, but also able to decode UUIDs and RFC3339Nano time
+// from strings. additional hooks are applied after those
+func mapstructureDecode(
+	input any,
+	output any,
+	hooks ...mapstructure.DecodeHookFunc,
+) error {
+	d, err := mapstructure.NewDecoder(
+		&mapstructure.DecoderConfig{
+			DecodeHook: mapstructure.ComposeDecodeHookFunc(
+				append(
+					[]mapstructure.DecodeHookFunc{
+						mapstructureDecodeHookUUID,
+						mapstructureDecodeHookBinary,
+						mapstructure.StringToTimeHookFunc(time.RFC3339Nano),
+					},
+					hooks...,
+				)...,
+			),
+			Result: output,
+		},
+	)
+	if err != nil {
+		return err
+	}
+	return d.Decode(input)
+}
 
+func mapstructureDecodeHookUUID(
+	from reflect.Type,
+	to reflect.Type,
+	data any,
+) (any, error) {
+	if from.Kind() != reflect.String ||
+		to != reflect.TypeOf((*gouuidtools.UUID)(nil)) {
+		return data, nil
 	}
+	return gouuidtools.NewUUIDFromString(data.(string))
 }
 
 func (self *ARPCNode) CallGetList(
@@ -1762,27 +2710,55 @@ func (self *ARPCNode) CallGetList(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.callGetList(
+		context.Background(),
+		response_timeout,
+	)
+}
+
+// like CallGetList(), but with context. see ARPCCallError
+func (self *ARPCNode) CallGetListCtx(
+	ctx context.Context,
+) (
+	buffer_id *gouuidtools.UUID,
+	err error,
+) {
+	buffer_id, timedout, closed, result_err, err := self.callGetList(
+		ctx,
+		self.ctxResponseTimeout(),
+	)
+	return buffer_id, newARPCCallError(
+		ctx, "CallGetList", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) callGetList(
+	ctx context.Context,
+	response_timeout time.Duration,
+) (
+	buffer_id *gouuidtools.UUID,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "CallGetList"
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		buffer_id = nil
@@ -1814,35 +2790,67 @@ func (self *ARPCNode) CallGetInfo(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.callGetInfo(
+		context.Background(),
+		call_id,
+		response_timeout,
+	)
+}
+
+// like CallGetInfo(), but with context. see ARPCCallError
+func (self *ARPCNode) CallGetInfoCtx(
+	ctx context.Context,
+	call_id *gouuidtools.UUID,
+) (
+	result *ARPCCallForJSON,
+	err error,
+) {
+	result, timedout, closed, result_err, err := self.callGetInfo(
+		ctx,
+		call_id,
+		self.ctxResponseTimeout(),
+	)
+	return result, newARPCCallError(
+		ctx, "CallGetInfo", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) callGetInfo(
+	ctx context.Context,
+	call_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	result *ARPCCallForJSON,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "CallGetInfo"
 	msg.Params = map[string]any{"call_id": call_id.Format()}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		result = nil
 		return
 	}
 
-	err = mapstructure.Decode(result_any, &result)
+	err = mapstructureDecode(result_any, &result)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
@@ -1850,6 +2858,85 @@ func (self *ARPCNode) CallGetInfo(
 	return result, false, false, nil, nil
 }
 
+func (self *ARPCNode) CallGetName(
+	call_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	name string,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
+	return self.callGetName(
+		context.Background(),
+		call_id,
+		response_timeout,
+	)
+}
+
+// like CallGetName(), but with context. see ARPCCallError
+func (self *ARPCNode) CallGetNameCtx(
+	ctx context.Context,
+	call_id *gouuidtools.UUID,
+) (
+	name string,
+	err error,
+) {
+	name, timedout, closed, result_err, err := self.callGetName(
+		ctx,
+		call_id,
+		self.ctxResponseTimeout(),
+	)
+	return name, newARPCCallError(
+		ctx, "CallGetName", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) callGetName(
+	ctx context.Context,
+	call_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	name string,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "CallGetName"
+	msg.Params = map[string]any{"call_id": call_id.Format()}
+
+	req := self.newPendingRequest()
+	defer req.cancel()
+
+	err = self.sendRequest(
+		msg,
+		req,
+		requestTimeout(ctx, response_timeout),
+	)
+	if err != nil {
+		return "", false, false, nil, err
+	}
+
+	result_any, timedout, closed, result_err, err :=
+		self.subResultGetter01(ctx, req)
+
+	if timedout || closed || result_err != nil || err != nil {
+		name = ""
+		return
+	}
+
+	result, ok := result_any.(string)
+	if !ok {
+		return "",
+			false, false, nil, errors.New("result must be string")
+	}
+
+	return result, false, false, nil, nil
+}
+
 func (self *ARPCNode) CallGetArgCount(
 	call_id *gouuidtools.UUID,
 	response_timeout time.Duration,
@@ -1860,44 +2947,118 @@ func (self *ARPCNode) CallGetArgCount(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.callGetArgCount(
+		context.Background(),
+		call_id,
+		response_timeout,
+	)
+}
+
+// like CallGetArgCount(), but with context. see ARPCCallError
+func (self *ARPCNode) CallGetArgCountCtx(
+	ctx context.Context,
+	call_id *gouuidtools.UUID,
+) (
+	res int,
+	err error,
+) {
+	res, timedout, closed, result_err, err := self.callGetArgCount(
+		ctx,
+		call_id,
+		self.ctxResponseTimeout(),
+	)
+	return res, newARPCCallError(
+		ctx, "CallGetArgCount", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) callGetArgCount(
+	ctx context.Context,
+	call_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	res int,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "CallGetArgCount"
 	msg.Params = map[string]any{"call_id": call_id.Format()}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return 0, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		res = 0
 		return
 	}
 
-	result, ok := result_any.(int)
+	result, ok := anyToInt(result_any)
 	if !ok {
 		return 0,
-			false, false, nil, errors.New("result must be uuid string")
+			false, false, nil, errors.New("result must be int")
 	}
 
-	return result, false, false, nil, nil
+	return result, false, false, nil, nil
+}
+
+func (self *ARPCNode) CallGetArgValues(
+	call_id *gouuidtools.UUID,
+	first, last int,
+	response_timeout time.Duration,
+) (
+	res []*ARPCArgInfo,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
+	return self.callGetArgValues(
+		context.Background(),
+		call_id,
+		first,
+		last,
+		response_timeout,
+	)
+}
+
+// like CallGetArgValues(), but with context. see ARPCCallError
+func (self *ARPCNode) CallGetArgValuesCtx(
+	ctx context.Context,
+	call_id *gouuidtools.UUID,
+	first, last int,
+) (
+	res []*ARPCArgInfo,
+	err error,
+) {
+	res, timedout, closed, result_err, err := self.callGetArgValues(
+		ctx,
+		call_id,
+		first,
+		last,
+		self.ctxResponseTimeout(),
+	)
+	return res, newARPCCallError(
+		ctx, "CallGetArgValues", timedout, closed, result_err, err,
+	)
 }
 
-func (self *ARPCNode) CallGetArgValues(
+func (self *ARPCNode) callGetArgValues(
+	ctx context.Context,
 	call_id *gouuidtools.UUID,
 	first, last int,
 	response_timeout time.Duration,
@@ -1908,7 +3069,6 @@ func (self *ARPCNode) CallGetArgValues(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "CallGetArgValue"
 	msg.Params = map[string]any{
@@ -1917,23 +3077,20 @@ func (self *ARPCNode) CallGetArgValues(
 		"last":    last,
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		res = nil
@@ -1942,7 +3099,7 @@ func (self *ARPCNode) CallGetArgValues(
 
 	var result []*ARPCArgInfo
 
-	err = mapstructure.Decode(result_any, &result)
+	err = mapstructureDecode(result_any, &result)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
@@ -1959,30 +3116,60 @@ func (self *ARPCNode) CallClose(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.callClose(
+		context.Background(),
+		call_id,
+		response_timeout,
+	)
+}
+
+// like CallClose(), but with context. see ARPCCallError
+func (self *ARPCNode) CallCloseCtx(
+	ctx context.Context,
+	call_id *gouuidtools.UUID,
+) (
+	err error,
+) {
+	timedout, closed, result_err, err := self.callClose(
+		ctx,
+		call_id,
+		self.ctxResponseTimeout(),
+	)
+	return newARPCCallError(
+		ctx, "CallClose", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) callClose(
+	ctx context.Context,
+	call_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "CallClose"
 	msg.Params = map[string]any{
 		"call_id": call_id.Format(),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return false, false, nil, err
 	}
 
 	_, timedout, closed, result_err, err =
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		return
@@ -2005,30 +3192,62 @@ func (self *ARPCNode) BufferGetInfo(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.bufferGetInfo(
+		context.Background(),
+		buffer_id,
+		response_timeout,
+	)
+}
+
+// like BufferGetInfo(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferGetInfoCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+) (
+	info *ARPCBufferInfo,
+	err error,
+) {
+	info, timedout, closed, result_err, err := self.bufferGetInfo(
+		ctx,
+		buffer_id,
+		self.ctxResponseTimeout(),
+	)
+	return info, newARPCCallError(
+		ctx, "BufferGetInfo", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferGetInfo(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	info *ARPCBufferInfo,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferGetInfo"
 	msg.Params = map[string]any{
 		"buffer_id": buffer_id.Format(),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		info = nil
@@ -2037,7 +3256,7 @@ func (self *ARPCNode) BufferGetInfo(
 
 	var result *ARPCBufferInfo
 
-	err = mapstructure.Decode(result_any, &result)
+	err = mapstructureDecode(result_any, &result)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
@@ -2055,40 +3274,72 @@ func (self *ARPCNode) BufferGetItemsCount(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.bufferGetItemsCount(
+		context.Background(),
+		buffer_id,
+		response_timeout,
+	)
+}
+
+// like BufferGetItemsCount(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferGetItemsCountCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+) (
+	count int,
+	err error,
+) {
+	count, timedout, closed, result_err, err := self.bufferGetItemsCount(
+		ctx,
+		buffer_id,
+		self.ctxResponseTimeout(),
+	)
+	return count, newARPCCallError(
+		ctx, "BufferGetItemsCount", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferGetItemsCount(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	count int,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferGetItemsCount"
 	msg.Params = map[string]any{
 		"buffer_id": buffer_id.Format(),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return 0, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		count = 0
 		return
 	}
 
-	result, ok := result_any.(int)
+	result, ok := anyToInt(result_any)
 	if !ok {
 		return 0,
-			false, false, nil, errors.New("result must be uuid string")
+			false, false, nil, errors.New("result must be int")
 	}
 
 	return result, false, false, nil, nil
@@ -2107,40 +3358,169 @@ func (self *ARPCNode) BufferGetItemsIds(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.bufferGetItemsIds(
+		context.Background(),
+		buffer_id,
+		first_spec,
+		last_spec,
+		response_timeout,
+	)
+}
+
+// like BufferGetItemsIds(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferGetItemsIdsCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	first_spec, last_spec *ARPCBufferItemSpecifier,
+) (
+	ids []string,
+	err error,
+) {
+	ids, timedout, closed, result_err, err := self.bufferGetItemsIds(
+		ctx,
+		buffer_id,
+		first_spec,
+		last_spec,
+		self.ctxResponseTimeout(),
+	)
+	return ids, newARPCCallError(
+		ctx, "BufferGetItemsIds", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferGetItemsIds(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	first_spec, last_spec *ARPCBufferItemSpecifier,
+	response_timeout time.Duration,
+) (
+	ids []string,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferGetItemsIds"
 	msg.Params = map[string]any{
-		"buffer_id": buffer_id.Format(),
+		"buffer_id":  buffer_id.Format(),
+		"first_spec": first_spec.Value,
+		"last_spec":  last_spec.Value,
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		ids = nil
 		return
 	}
 
-	result, ok := result_any.([]string)
-	if !ok {
-		return nil,
-			false, false, nil, errors.New("result must be uuid string")
+	var result []string
+
+	err = mapstructureDecode(result_any, &result)
+	if err != nil {
+		return nil, false, false, nil, err
+	}
+
+	return result, false, false, nil, nil
+}
+
+func (self *ARPCNode) BufferGetItemsTimesByIds(
+	buffer_id *gouuidtools.UUID,
+	ids []string,
+	response_timeout time.Duration,
+) (
+	times []time.Time,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
+	return self.bufferGetItemsTimesByIds(
+		context.Background(),
+		buffer_id,
+		ids,
+		response_timeout,
+	)
+}
+
+// like BufferGetItemsTimesByIds(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferGetItemsTimesByIdsCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	ids []string,
+) (
+	times []time.Time,
+	err error,
+) {
+	times, timedout, closed, result_err, err := self.bufferGetItemsTimesByIds(
+		ctx,
+		buffer_id,
+		ids,
+		self.ctxResponseTimeout(),
+	)
+	return times, newARPCCallError(
+		ctx, "BufferGetItemsTimesByIds", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferGetItemsTimesByIds(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	ids []string,
+	response_timeout time.Duration,
+) (
+	times []time.Time,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferGetItemsTimesByIds"
+	msg.Params = map[string]any{
+		"buffer_id": buffer_id.Format(),
+		"ids":       ids,
+	}
+
+	req := self.newPendingRequest()
+	defer req.cancel()
+
+	err = self.sendRequest(
+		msg,
+		req,
+		requestTimeout(ctx, response_timeout),
+	)
+	if err != nil {
+		return nil, false, false, nil, err
+	}
+
+	result_any, timedout, closed, result_err, err :=
+		self.subResultGetter01(ctx, req)
+
+	if timedout || closed || result_err != nil || err != nil {
+		times = nil
+		return
+	}
+
+	var result []time.Time
+
+	err = mapstructureDecode(result_any, &result)
+	if err != nil {
+		return nil, false, false, nil, err
 	}
 
 	return result, false, false, nil, nil
@@ -2157,7 +3537,46 @@ func (self *ARPCNode) BufferGetItemsByIds(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.bufferGetItemsByIds(
+		context.Background(),
+		buffer_id,
+		ids,
+		response_timeout,
+	)
+}
+
+// like BufferGetItemsByIds(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferGetItemsByIdsCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	ids []string,
+) (
+	buffer_items []*ARPCBufferItem,
+	err error,
+) {
+	buffer_items, timedout, closed, result_err, err := self.bufferGetItemsByIds(
+		ctx,
+		buffer_id,
+		ids,
+		self.ctxResponseTimeout(),
+	)
+	return buffer_items, newARPCCallError(
+		ctx, "BufferGetItemsByIds", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferGetItemsByIds(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	ids []string,
+	response_timeout time.Duration,
+) (
+	buffer_items []*ARPCBufferItem,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferGetItemsByIds"
 	msg.Params = map[string]any{
@@ -2165,23 +3584,20 @@ func (self *ARPCNode) BufferGetItemsByIds(
 		"ids":       ids,
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		buffer_items = nil
@@ -2191,18 +3607,18 @@ func (self *ARPCNode) BufferGetItemsByIds(
 	result_any_slice, ok := result_any.([]any)
 	if !ok {
 		return nil,
-			false, false, nil, errors.New("result must be uuid string")
+			false, false, nil, errors.New("result must be array")
 	}
 
 	result := make([]*ARPCBufferItem, 0)
 
 	for _, i := range result_any_slice {
 		var x *ARPCBufferItem
-		x = nil
-		err = mapstructure.Decode(i, &x)
+		err = mapstructureDecode(i, &x)
 		if err != nil {
 			return nil, false, false, nil, err
 		}
+		result = append(result, x)
 	}
 
 	return result, false, false, nil, nil
@@ -2218,30 +3634,62 @@ func (self *ARPCNode) BufferGetItemsFirstTime(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.bufferGetItemsFirstTime(
+		context.Background(),
+		buffer_id,
+		response_timeout,
+	)
+}
+
+// like BufferGetItemsFirstTime(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferGetItemsFirstTimeCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+) (
+	time_ time.Time,
+	err error,
+) {
+	time_, timedout, closed, result_err, err := self.bufferGetItemsFirstTime(
+		ctx,
+		buffer_id,
+		self.ctxResponseTimeout(),
+	)
+	return time_, newARPCCallError(
+		ctx, "BufferGetItemsFirstTime", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferGetItemsFirstTime(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	time_ time.Time,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferGetItemsFirstTime"
 	msg.Params = map[string]any{
 		"buffer_id": buffer_id.Format(),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return time.Time{}, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		time_ = time.Time{}
@@ -2254,7 +3702,7 @@ func (self *ARPCNode) BufferGetItemsFirstTime(
 			false, false, nil, errors.New("result must be RFC3339Nano time string")
 	}
 
-	result, err := time.Parse(result_str, time.RFC3339Nano)
+	result, err := time.Parse(time.RFC3339Nano, result_str)
 	if err != nil {
 		return time.Time{},
 			false, false, nil, err
@@ -2263,7 +3711,43 @@ func (self *ARPCNode) BufferGetItemsFirstTime(
 	return result, false, false, nil, nil
 }
 
-func (self *ARPCNode) BufferGetItemsLastTime(
+func (self *ARPCNode) BufferGetItemsLastTime(
+	buffer_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	time_ time.Time,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
+	return self.bufferGetItemsLastTime(
+		context.Background(),
+		buffer_id,
+		response_timeout,
+	)
+}
+
+// like BufferGetItemsLastTime(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferGetItemsLastTimeCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+) (
+	time_ time.Time,
+	err error,
+) {
+	time_, timedout, closed, result_err, err := self.bufferGetItemsLastTime(
+		ctx,
+		buffer_id,
+		self.ctxResponseTimeout(),
+	)
+	return time_, newARPCCallError(
+		ctx, "BufferGetItemsLastTime", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferGetItemsLastTime(
+	ctx context.Context,
 	buffer_id *gouuidtools.UUID,
 	response_timeout time.Duration,
 ) (
@@ -2273,30 +3757,26 @@ func (self *ARPCNode) BufferGetItemsLastTime(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
 	msg := new(gojsonrpc2.Message)
-	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferGetItemsIds"
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferGetItemsLastTime"
 	msg.Params = map[string]any{
 		"buffer_id": buffer_id.Format(),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return time.Time{}, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		time_ = time.Time{}
@@ -2309,7 +3789,7 @@ func (self *ARPCNode) BufferGetItemsLastTime(
 			false, false, nil, errors.New("result must be RFC3339Nano time string")
 	}
 
-	result, err := time.Parse(result_str, time.RFC3339Nano)
+	result, err := time.Parse(time.RFC3339Nano, result_str)
 	if err != nil {
 		return time.Time{}, false, false, nil, err
 	}
@@ -2326,30 +3806,60 @@ func (self *ARPCNode) BufferSubscribeOnUpdatesNotification(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.bufferSubscribeOnUpdatesNotification(
+		context.Background(),
+		buffer_id,
+		response_timeout,
+	)
+}
+
+// like BufferSubscribeOnUpdatesNotification(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferSubscribeOnUpdatesNotificationCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+) (
+	err error,
+) {
+	timedout, closed, result_err, err := self.bufferSubscribeOnUpdatesNotification(
+		ctx,
+		buffer_id,
+		self.ctxResponseTimeout(),
+	)
+	return newARPCCallError(
+		ctx, "BufferSubscribeOnUpdatesNotification", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferSubscribeOnUpdatesNotification(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferSubscribeOnUpdatesNotification"
 	msg.Params = map[string]any{
 		"buffer_id": buffer_id.Format(),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return false, false, nil, err
 	}
 
 	_, timedout, closed, result_err, err =
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		return
@@ -2367,30 +3877,60 @@ func (self *ARPCNode) BufferUnsubscribeFromUpdatesNotification(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.bufferUnsubscribeFromUpdatesNotification(
+		context.Background(),
+		buffer_id,
+		response_timeout,
+	)
+}
+
+// like BufferUnsubscribeFromUpdatesNotification(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferUnsubscribeFromUpdatesNotificationCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+) (
+	err error,
+) {
+	timedout, closed, result_err, err := self.bufferUnsubscribeFromUpdatesNotification(
+		ctx,
+		buffer_id,
+		self.ctxResponseTimeout(),
+	)
+	return newARPCCallError(
+		ctx, "BufferUnsubscribeFromUpdatesNotification", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferUnsubscribeFromUpdatesNotification(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferUnsubscribeFromUpdatesNotification"
 	msg.Params = map[string]any{
 		"buffer_id": buffer_id.Format(),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return false, false, nil, err
 	}
 
 	_, timedout, closed, result_err, err =
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		return
@@ -2409,30 +3949,62 @@ func (self *ARPCNode) BufferGetIsSubscribedOnUpdatesNotification(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.bufferGetIsSubscribedOnUpdatesNotification(
+		context.Background(),
+		buffer_id,
+		response_timeout,
+	)
+}
+
+// like BufferGetIsSubscribedOnUpdatesNotification(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferGetIsSubscribedOnUpdatesNotificationCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+) (
+	result bool,
+	err error,
+) {
+	result, timedout, closed, result_err, err := self.bufferGetIsSubscribedOnUpdatesNotification(
+		ctx,
+		buffer_id,
+		self.ctxResponseTimeout(),
+	)
+	return result, newARPCCallError(
+		ctx, "BufferGetIsSubscribedOnUpdatesNotification", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferGetIsSubscribedOnUpdatesNotification(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	result bool,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferGetIsSubscribedOnUpdatesNotification"
 	msg.Params = map[string]any{
 		"buffer_id": buffer_id.Format(),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return false, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		return
@@ -2441,10 +4013,10 @@ func (self *ARPCNode) BufferGetIsSubscribedOnUpdatesNotification(
 	result, ok := result_any.(bool)
 	if !ok {
 		return false,
-			false, false, nil, errors.New("result must be uuid string")
+			false, false, nil, errors.New("result must be bool")
 	}
 
-	return false, false, false, nil, nil
+	return result, false, false, nil, nil
 }
 
 func (self *ARPCNode) BufferGetListSubscribedUpdatesNotifications(
@@ -2456,27 +4028,55 @@ func (self *ARPCNode) BufferGetListSubscribedUpdatesNotifications(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.bufferGetListSubscribedUpdatesNotifications(
+		context.Background(),
+		response_timeout,
+	)
+}
+
+// like BufferGetListSubscribedUpdatesNotifications(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferGetListSubscribedUpdatesNotificationsCtx(
+	ctx context.Context,
+) (
+	buffer_id *gouuidtools.UUID,
+	err error,
+) {
+	buffer_id, timedout, closed, result_err, err := self.bufferGetListSubscribedUpdatesNotifications(
+		ctx,
+		self.ctxResponseTimeout(),
+	)
+	return buffer_id, newARPCCallError(
+		ctx, "BufferGetListSubscribedUpdatesNotifications", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferGetListSubscribedUpdatesNotifications(
+	ctx context.Context,
+	response_timeout time.Duration,
+) (
+	buffer_id *gouuidtools.UUID,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferGetListSubscribedUpdatesNotifications"
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		buffer_id = nil
@@ -2507,39 +4107,71 @@ func (self *ARPCNode) BufferBinaryGetSize(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.bufferBinaryGetSize(
+		context.Background(),
+		buffer_id,
+		response_timeout,
+	)
+}
+
+// like BufferBinaryGetSize(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferBinaryGetSizeCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+) (
+	size int,
+	err error,
+) {
+	size, timedout, closed, result_err, err := self.bufferBinaryGetSize(
+		ctx,
+		buffer_id,
+		self.ctxResponseTimeout(),
+	)
+	return size, newARPCCallError(
+		ctx, "BufferBinaryGetSize", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferBinaryGetSize(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	size int,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferBinaryGetSize"
 	msg.Params = map[string]any{
 		"buffer_id": buffer_id.Format(),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return 0, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		size = 0
 		return
 	}
 
-	result, ok := result_any.(int)
+	result, ok := anyToInt(result_any)
 	if !ok {
-		return 0, false, false, nil, errors.New("result must be uuid string")
+		return 0, false, false, nil, errors.New("result must be int")
 	}
 
 	return result, false, false, nil, nil
@@ -2556,7 +4188,48 @@ func (self *ARPCNode) BufferBinaryGetSlice(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.bufferBinaryGetSlice(
+		context.Background(),
+		buffer_id,
+		start_index,
+		end_index,
+		response_timeout,
+	)
+}
+
+// like BufferBinaryGetSlice(), but with context. see ARPCCallError
+func (self *ARPCNode) BufferBinaryGetSliceCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	start_index, end_index int,
+) (
+	data []byte,
+	err error,
+) {
+	data, timedout, closed, result_err, err := self.bufferBinaryGetSlice(
+		ctx,
+		buffer_id,
+		start_index,
+		end_index,
+		self.ctxResponseTimeout(),
+	)
+	return data, newARPCCallError(
+		ctx, "BufferBinaryGetSlice", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) bufferBinaryGetSlice(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	start_index, end_index int,
+	response_timeout time.Duration,
+) (
+	data []byte,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "BufferBinaryGetSlice"
 	msg.Params = map[string]any{
@@ -2565,30 +4238,32 @@ func (self *ARPCNode) BufferBinaryGetSlice(
 		"end_index":   end_index,
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
-	_, timedout, closed, result_err, err =
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+	result_any, timedout, closed, result_err, err :=
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
-		buffer_id = nil
+		data = nil
 		return
 	}
 
-	return nil, false, false, nil, nil
+	result, err := decodeARPCBinary(result_any)
+	if err != nil {
+		return nil, false, false, nil, err
+	}
+
+	return result, false, false, nil, nil
 }
 
 // ----------------------------------------
@@ -2604,27 +4279,55 @@ func (self *ARPCNode) TransmissionGetList(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.transmissionGetList(
+		context.Background(),
+		response_timeout,
+	)
+}
+
+// like TransmissionGetList(), but with context. see ARPCCallError
+func (self *ARPCNode) TransmissionGetListCtx(
+	ctx context.Context,
+) (
+	buffer_id *gouuidtools.UUID,
+	err error,
+) {
+	buffer_id, timedout, closed, result_err, err := self.transmissionGetList(
+		ctx,
+		self.ctxResponseTimeout(),
+	)
+	return buffer_id, newARPCCallError(
+		ctx, "TransmissionGetList", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) transmissionGetList(
+	ctx context.Context,
+	response_timeout time.Duration,
+) (
+	buffer_id *gouuidtools.UUID,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "TransmissionGetList"
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		buffer_id = nil
@@ -2655,30 +4358,62 @@ func (self *ARPCNode) TransmissionGetInfo(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.transmissionGetInfo(
+		context.Background(),
+		transmission_id,
+		response_timeout,
+	)
+}
+
+// like TransmissionGetInfo(), but with context. see ARPCCallError
+func (self *ARPCNode) TransmissionGetInfoCtx(
+	ctx context.Context,
+	transmission_id *gouuidtools.UUID,
+) (
+	info *ARPCTransmissionInfo,
+	err error,
+) {
+	info, timedout, closed, result_err, err := self.transmissionGetInfo(
+		ctx,
+		transmission_id,
+		self.ctxResponseTimeout(),
+	)
+	return info, newARPCCallError(
+		ctx, "TransmissionGetInfo", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) transmissionGetInfo(
+	ctx context.Context,
+	transmission_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	info *ARPCTransmissionInfo,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "TransmissionGetInfo"
 	msg.Params = map[string]any{
 		"transmission_id": transmission_id.Format(),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		info = nil
@@ -2687,7 +4422,7 @@ func (self *ARPCNode) TransmissionGetInfo(
 
 	var result *ARPCTransmissionInfo
 
-	err = mapstructure.Decode(result_any, &result)
+	err = mapstructureDecode(result_any, &result)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
@@ -2708,27 +4443,55 @@ func (self *ARPCNode) SocketGetList(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.socketGetList(
+		context.Background(),
+		response_timeout,
+	)
+}
+
+// like SocketGetList(), but with context. see ARPCCallError
+func (self *ARPCNode) SocketGetListCtx(
+	ctx context.Context,
+) (
+	buffer_id *gouuidtools.UUID,
+	err error,
+) {
+	buffer_id, timedout, closed, result_err, err := self.socketGetList(
+		ctx,
+		self.ctxResponseTimeout(),
+	)
+	return buffer_id, newARPCCallError(
+		ctx, "SocketGetList", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) socketGetList(
+	ctx context.Context,
+	response_timeout time.Duration,
+) (
+	buffer_id *gouuidtools.UUID,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "SocketGetList"
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		buffer_id = nil
@@ -2749,7 +4512,43 @@ func (self *ARPCNode) SocketGetList(
 	return result, false, false, nil, nil
 }
 
-func (self *ARPCNode) SocketOpen(
+func (self *ARPCNode) SocketOpen(
+	listening_socket_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	connected_socket_id *gouuidtools.UUID,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
+	return self.socketOpen(
+		context.Background(),
+		listening_socket_id,
+		response_timeout,
+	)
+}
+
+// like SocketOpen(), but with context. see ARPCCallError
+func (self *ARPCNode) SocketOpenCtx(
+	ctx context.Context,
+	listening_socket_id *gouuidtools.UUID,
+) (
+	connected_socket_id *gouuidtools.UUID,
+	err error,
+) {
+	connected_socket_id, timedout, closed, result_err, err := self.socketOpen(
+		ctx,
+		listening_socket_id,
+		self.ctxResponseTimeout(),
+	)
+	return connected_socket_id, newARPCCallError(
+		ctx, "SocketOpen", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) socketOpen(
+	ctx context.Context,
 	listening_socket_id *gouuidtools.UUID,
 	response_timeout time.Duration,
 ) (
@@ -2759,30 +4558,26 @@ func (self *ARPCNode) SocketOpen(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "SocketOpen"
 	msg.Params = map[string]any{
 		"listening_socket_id": listening_socket_id.Format(),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		connected_socket_id = nil
@@ -2814,7 +4609,46 @@ func (self *ARPCNode) SocketRead(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.socketRead(
+		context.Background(),
+		connected_socket_id,
+		try_read_size,
+		response_timeout,
+	)
+}
+
+// like SocketRead(), but with context. see ARPCCallError
+func (self *ARPCNode) SocketReadCtx(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	try_read_size int,
+) (
+	b []byte,
+	err error,
+) {
+	b, timedout, closed, result_err, err := self.socketRead(
+		ctx,
+		connected_socket_id,
+		try_read_size,
+		self.ctxResponseTimeout(),
+	)
+	return b, newARPCCallError(
+		ctx, "SocketRead", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) socketRead(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	try_read_size int,
+	response_timeout time.Duration,
+) (
+	b []byte,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "SocketRead"
 	msg.Params = map[string]any{
@@ -2822,33 +4656,29 @@ func (self *ARPCNode) SocketRead(
 		"try_read_size":       try_read_size,
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return nil, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		b = nil
 		return
 	}
 
-	result, ok := result_any.([]byte)
-	if !ok {
-		return nil,
-			false, false, nil, errors.New("result must be uuid string")
+	result, err := decodeARPCBinary(result_any)
+	if err != nil {
+		return nil, false, false, nil, err
 	}
 
 	return result, false, false, nil, nil
@@ -2865,41 +4695,77 @@ func (self *ARPCNode) SocketWrite(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.socketWrite(
+		context.Background(),
+		connected_socket_id,
+		b,
+		response_timeout,
+	)
+}
+
+// like SocketWrite(), but with context. see ARPCCallError
+func (self *ARPCNode) SocketWriteCtx(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	b []byte,
+) (
+	n int,
+	err error,
+) {
+	n, timedout, closed, result_err, err := self.socketWrite(
+		ctx,
+		connected_socket_id,
+		b,
+		self.ctxResponseTimeout(),
+	)
+	return n, newARPCCallError(
+		ctx, "SocketWrite", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) socketWrite(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	b []byte,
+	response_timeout time.Duration,
+) (
+	n int,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "SocketWrite"
 	msg.Params = map[string]any{
 		"connected_socket_id": connected_socket_id.Format(),
-		"b":                   b,
+		"b":                   encodeARPCBinary(b),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return 0, false, false, nil, err
 	}
 
 	result_any, timedout, closed, result_err, err :=
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		n = 0
 		return
 	}
 
-	result, ok := result_any.(int)
+	result, ok := anyToInt(result_any)
 	if !ok {
 		return 0,
-			false, false, nil, errors.New("result must be uuid string")
+			false, false, nil, errors.New("result must be int")
 	}
 
 	return result, false, false, nil, nil
@@ -2914,30 +4780,60 @@ func (self *ARPCNode) SocketClose(
 	result_err error,
 	err error,
 ) {
-	self.nodeInvalidStateException()
+	return self.socketClose(
+		context.Background(),
+		connected_socket_id,
+		response_timeout,
+	)
+}
+
+// like SocketClose(), but with context. see ARPCCallError
+func (self *ARPCNode) SocketCloseCtx(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+) (
+	err error,
+) {
+	timedout, closed, result_err, err := self.socketClose(
+		ctx,
+		connected_socket_id,
+		self.ctxResponseTimeout(),
+	)
+	return newARPCCallError(
+		ctx, "SocketClose", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) socketClose(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
 	msg := new(gojsonrpc2.Message)
-	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "CallClose"
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "SocketClose"
 	msg.Params = map[string]any{
 		"connected_socket_id": connected_socket_id.Format(),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return false, false, nil, err
 	}
 
 	_, timedout, closed, result_err, err =
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		return
@@ -2955,6 +4851,44 @@ func (self *ARPCNode) SocketSetDeadline(
 	closed bool,
 	result_err error,
 	err error,
+) {
+	return self.socketSetDeadline(
+		context.Background(),
+		connected_socket_id,
+		t,
+		response_timeout,
+	)
+}
+
+// like SocketSetDeadline(), but with context. see ARPCCallError
+func (self *ARPCNode) SocketSetDeadlineCtx(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	t time.Time,
+) (
+	err error,
+) {
+	timedout, closed, result_err, err := self.socketSetDeadline(
+		ctx,
+		connected_socket_id,
+		t,
+		self.ctxResponseTimeout(),
+	)
+	return newARPCCallError(
+		ctx, "SocketSetDeadline", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) socketSetDeadline(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	t time.Time,
+	response_timeout time.Duration,
+) (
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
 ) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "SocketSetDeadline"
@@ -2963,23 +4897,20 @@ func (self *ARPCNode) SocketSetDeadline(
 		"t":                   t.Format(time.RFC3339Nano),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return false, false, nil, err
 	}
 
 	_, timedout, closed, result_err, err =
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		return
@@ -2997,6 +4928,44 @@ func (self *ARPCNode) SocketSetReadDeadline(
 	closed bool,
 	result_err error,
 	err error,
+) {
+	return self.socketSetReadDeadline(
+		context.Background(),
+		connected_socket_id,
+		t,
+		response_timeout,
+	)
+}
+
+// like SocketSetReadDeadline(), but with context. see ARPCCallError
+func (self *ARPCNode) SocketSetReadDeadlineCtx(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	t time.Time,
+) (
+	err error,
+) {
+	timedout, closed, result_err, err := self.socketSetReadDeadline(
+		ctx,
+		connected_socket_id,
+		t,
+		self.ctxResponseTimeout(),
+	)
+	return newARPCCallError(
+		ctx, "SocketSetReadDeadline", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) socketSetReadDeadline(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	t time.Time,
+	response_timeout time.Duration,
+) (
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
 ) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "SocketSetReadDeadline"
@@ -3005,23 +4974,20 @@ func (self *ARPCNode) SocketSetReadDeadline(
 		"t":                   t.Format(time.RFC3339Nano),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
-		response_timeout,
-		nil,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return false, false, nil, err
 	}
 
 	_, timedout, closed, result_err, err =
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		return
@@ -3039,6 +5005,44 @@ func (self *ARPCNode) SocketSetWriteDeadline(
 	closed bool,
 	result_err error,
 	err error,
+) {
+	return self.socketSetWriteDeadline(
+		context.Background(),
+		connected_socket_id,
+		t,
+		response_timeout,
+	)
+}
+
+// like SocketSetWriteDeadline(), but with context. see ARPCCallError
+func (self *ARPCNode) SocketSetWriteDeadlineCtx(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	t time.Time,
+) (
+	err error,
+) {
+	timedout, closed, result_err, err := self.socketSetWriteDeadline(
+		ctx,
+		connected_socket_id,
+		t,
+		self.ctxResponseTimeout(),
+	)
+	return newARPCCallError(
+		ctx, "SocketSetWriteDeadline", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) socketSetWriteDeadline(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	t time.Time,
+	response_timeout time.Duration,
+) (
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
 ) {
 	msg := new(gojsonrpc2.Message)
 	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "SocketSetWriteDeadline"
@@ -3047,23 +5051,97 @@ func (self *ARPCNode) SocketSetWriteDeadline(
 		"t":                   t.Format(time.RFC3339Nano),
 	}
 
-	timedout_sig, closed_sig, msg_sig, rh :=
-		gojsonrpc2.NewChannelledJSONRPC2NodeRespHandler()
+	req := self.newPendingRequest()
+	defer req.cancel()
 
-	_, err = self.jrpc_node.SendRequest(
+	err = self.sendRequest(
 		msg,
-		true,
-		false,
-		rh,
+		req,
+		requestTimeout(ctx, response_timeout),
+	)
+	if err != nil {
+		return false, false, nil, err
+	}
+
+	_, timedout, closed, result_err, err =
+		self.subResultGetter01(ctx, req)
+
+	if timedout || closed || result_err != nil || err != nil {
+		return
+	}
+
+	return false, false, nil, nil
+}
+
+func (self *ARPCNode) SocketStreamStart(
+	connected_socket_id *gouuidtools.UUID,
+	window int,
+	response_timeout time.Duration,
+) (
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
+	return self.socketStreamStart(
+		context.Background(),
+		connected_socket_id,
+		window,
 		response_timeout,
-		nil,
+	)
+}
+
+// like SocketStreamStart(), but with context. see ARPCCallError
+func (self *ARPCNode) SocketStreamStartCtx(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	window int,
+) (
+	err error,
+) {
+	timedout, closed, result_err, err := self.socketStreamStart(
+		ctx,
+		connected_socket_id,
+		window,
+		self.ctxResponseTimeout(),
+	)
+	return newARPCCallError(
+		ctx, "SocketStreamStart", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) socketStreamStart(
+	ctx context.Context,
+	connected_socket_id *gouuidtools.UUID,
+	window int,
+	response_timeout time.Duration,
+) (
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "SocketStreamStart"
+	msg.Params = map[string]any{
+		"connected_socket_id": connected_socket_id.Format(),
+		"window":              window,
+	}
+
+	req := self.newPendingRequest()
+	defer req.cancel()
+
+	err = self.sendRequest(
+		msg,
+		req,
+		requestTimeout(ctx, response_timeout),
 	)
 	if err != nil {
 		return false, false, nil, err
 	}
 
 	_, timedout, closed, result_err, err =
-		self.subResultGetter01(timedout_sig, closed_sig, msg_sig)
+		self.subResultGetter01(ctx, req)
 
 	if timedout || closed || result_err != nil || err != nil {
 		return
@@ -3071,3 +5149,89 @@ func (self *ARPCNode) SocketSetWriteDeadline(
 
 	return false, false, nil, nil
 }
+
+func (self *ARPCNode) Touch(
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	ttl time.Duration,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
+	return self.touch(
+		context.Background(),
+		object_type,
+		object_id,
+		response_timeout,
+	)
+}
+
+// like Touch(), but with context. see ARPCCallError
+func (self *ARPCNode) TouchCtx(
+	ctx context.Context,
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+) (
+	ttl time.Duration,
+	err error,
+) {
+	ttl, timedout, closed, result_err, err := self.touch(
+		ctx,
+		object_type,
+		object_id,
+		self.ctxResponseTimeout(),
+	)
+	return ttl, newARPCCallError(
+		ctx, "Touch", timedout, closed, result_err, err,
+	)
+}
+
+func (self *ARPCNode) touch(
+	ctx context.Context,
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) (
+	ttl time.Duration,
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) {
+	msg := new(gojsonrpc2.Message)
+	msg.Method = ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + "Touch"
+	msg.Params = map[string]any{
+		"object_type": string(object_type),
+		"object_id":   object_id.Format(),
+	}
+
+	req := self.newPendingRequest()
+	defer req.cancel()
+
+	err = self.sendRequest(
+		msg,
+		req,
+		requestTimeout(ctx, response_timeout),
+	)
+	if err != nil {
+		return 0, false, false, nil, err
+	}
+
+	result_any, timedout, closed, result_err, err :=
+		self.subResultGetter01(ctx, req)
+
+	if timedout || closed || result_err != nil || err != nil {
+		ttl = 0
+		return
+	}
+
+	result, ok := anyToInt(result_any)
+	if !ok {
+		return 0, false, false, nil, errors.New("result must be int")
+	}
+
+	return time.Duration(result) * time.Millisecond, false, false, nil, nil
+}
@@ -3,8 +3,9 @@ package goarpcsolution
 import (
 	"errors"
 	"fmt"
-	"log"
+	"io"
 	"net"
+	"os"
 	"sync"
 	"time"
 
@@ -12,8 +13,6 @@ import (
 	"github.com/AnimusPEXUS/gorecursionguard"
 	"github.com/AnimusPEXUS/goreentrantlock"
 	"github.com/AnimusPEXUS/gouuidtools"
-
-	"github.com/AnimusPEXUS/goworker"
 )
 
 // todo: find better place for this.
@@ -21,10 +20,25 @@ import (
 // to be variable
 const TTL_CONST_10MIN = time.Duration(time.Minute * 10)
 
+// count of ids of expired objects, which controller remembers to answer
+// ARPCErrorCodeExpired on requests to them
+const ARPC_EXPIRED_IDS_MAX = 1024
+
+// most ids, returned by one BufferGetItemsIds() (rest of range is
+// requested by continuing from last returned id) and accepted by
+// BufferGetItemsByIds() and BufferGetItemsTimesByIds()
+const ARPC_BUFFER_ITEMS_IDS_MAX_COUNT = 1000
+
+// longest slice, which remote node can request with
+// BufferBinaryGetSlice(). slice is read into memory as whole
+const ARPC_BUFFER_BINARY_MAX_SLICE_SIZE = 16 * ARPC_REMOTE_CONN_MAX_READ_SIZE
+
 var _ ARPCNodeCtlI = &ARPCNodeCtlBasic{}
 
 type ARPCNodeCtlBasic struct {
-	// the resulting errors are returned to PushMessageFromOutsied caller.
+	// call is fetched from remote node before passed here.
+	// calls are handled in separate goroutine, so the resulting errors are
+	// passed to node's OnAsyncHandlerErrorsCB.
 	//   error #0 - if protocol error
 	//   error #1 - error preventing normal error response
 	OnCallCB            func(call *ARPCCall) (error, error)
@@ -35,6 +49,28 @@ type ARPCNodeCtlBasic struct {
 	//   error #1 - error preventing normal error response
 	OnSimpleRequestCB func(msg *gojsonrpc2.Message) (error, error)
 
+	// called when remote buffer, on which this node is subscribed, changes
+	OnBufferUpdatedCB func(buffer_id *gouuidtools.UUID)
+
+	// called when remote node publishes buffer, transmission or listening
+	// socket
+	OnNewBufferCB       func(buffer_id *gouuidtools.UUID)
+	OnNewTransmissionCB func(transmission_id *gouuidtools.UUID)
+	OnNewSocketCB       func(listening_socket_id *gouuidtools.UUID)
+
+	// called when remote buffer or transmission is finished
+	OnObjectFinishedCB func(
+		object_type ARPCObjectType,
+		object_id *gouuidtools.UUID,
+	)
+
+	// called when remote node deletes object
+	OnObjectRemovedCB func(
+		object_type ARPCObjectType,
+		object_id *gouuidtools.UUID,
+		reason ARPCObjectRemoveReason,
+	)
+
 	call_id_r             *gouuidtools.UUIDRegistry
 	buffer_id_r           *gouuidtools.UUIDRegistry
 	transmission_id_r     *gouuidtools.UUIDRegistry
@@ -53,10 +89,33 @@ type ARPCNodeCtlBasic struct {
 	listening_sockets []*ARPCNodeCtlBasicListeningSocketR
 	connected_sockets []*ARPCNodeCtlBasicConnectedSocketR
 
+	// default TTLs of objects, for which TTL isn't specified.
+	// object is deleted if it's not accessed or touched (see Touch())
+	// during it's TTL. default values are TTL_CONST_10MIN
+	DefaultCallTTL            time.Duration
+	DefaultBufferTTL          time.Duration
+	DefaultTransmissionTTL    time.Duration
+	DefaultListeningSocketTTL time.Duration
+	DefaultConnectedSocketTTL time.Duration
+
+	// timeout for requests which controller makes by itself
+	// (for instance, to get info of new calls)
+	ResponseTimeout time.Duration
+
 	handlers_mtx *sync.Mutex
 	handlers     []*xARPCNodeCtlBasicCallResHandlerWrapper
 
-	wrkr01 *goworker.Worker
+	// connections, to which remote node pushes socket data
+	stream_conns_mtx *sync.Mutex
+	stream_conns     map[string]*ARPCRemoteConn
+
+	// expires leases of records and response handlers
+	expiry *xARPCExpiryScheduler
+
+	// ids of recently expired objects (see rememberExpired())
+	expired_ids_mtx   *sync.Mutex
+	expired_ids       map[string]struct{}
+	expired_ids_order []string
 
 	stop_flag bool
 
@@ -70,7 +129,13 @@ type ARPCNodeCtlBasic struct {
 }
 
 func NewARPCNodeCtlBasic() *ARPCNodeCtlBasic {
+	return NewARPCNodeCtlBasicWithClock(ARPCClockSystem)
+}
+
+// clock is used for expiry of records and of response handlers
+func NewARPCNodeCtlBasicWithClock(clock ARPCClock) *ARPCNodeCtlBasic {
 	self := new(ARPCNodeCtlBasic)
+	self.expiry = newARPCExpiryScheduler(clock)
 	self.debugName = "ARPCNodeCtlBasic"
 	self.debug = true
 
@@ -85,6 +150,22 @@ func NewARPCNodeCtlBasic() *ARPCNodeCtlBasic {
 	self.listening_sockets_mtx = goreentrantlock.NewReentrantMutexCheckable(false)
 	self.connected_sockets_mtx = goreentrantlock.NewReentrantMutexCheckable(false)
 
+	self.handlers_mtx = new(sync.Mutex)
+
+	self.stream_conns_mtx = new(sync.Mutex)
+	self.stream_conns = make(map[string]*ARPCRemoteConn)
+
+	self.expired_ids_mtx = new(sync.Mutex)
+	self.expired_ids = make(map[string]struct{})
+
+	self.ResponseTimeout = time.Minute
+
+	self.DefaultCallTTL = TTL_CONST_10MIN
+	self.DefaultBufferTTL = TTL_CONST_10MIN
+	self.DefaultTransmissionTTL = TTL_CONST_10MIN
+	self.DefaultListeningSocketTTL = TTL_CONST_10MIN
+	self.DefaultConnectedSocketTTL = TTL_CONST_10MIN
+
 	{
 		r, err := gouuidtools.NewUUIDRegistry()
 		if err != nil {
@@ -125,8 +206,6 @@ func NewARPCNodeCtlBasic() *ARPCNodeCtlBasic {
 		self.connected_socket_id_r = r
 	}
 
-	self.wrkr01 = goworker.New(self.worker01)
-	self.wrkr01.Start()
 	return self
 }
 
@@ -158,6 +237,10 @@ func (self *ARPCNodeCtlBasic) Close() {
 	self.closeRecursionGuard.Do(
 		func() {
 			self.stop_flag = true
+			self.closeHandlers()
+			self.closeStreamConns()
+			self.deleteAll()
+			self.expiry.close()
 			if self.node != nil {
 				self.node.Close()
 				self.node = nil
@@ -166,340 +249,861 @@ func (self *ARPCNodeCtlBasic) Close() {
 	)
 }
 
-const (
-	worker01_interval_cleanup = time.Minute
-)
-
-func (self *ARPCNodeCtlBasic) worker01(
-	set_starting func(),
-	set_working func(),
-	set_stopping func(),
-	set_stopped func(),
-
-	is_stop_flag func() bool,
-) {
-
-	set_starting()
-	defer func() {
-		for _, i := range self.calls[:] {
-			self.deleteCallR(i)
-		}
-
-		for _, i := range self.buffers[:] {
-			self.deleteBufferR(i)
-		}
-
-		for _, i := range self.transmissions[:] {
-			self.deleteTransmissionR(i)
-		}
-
-		for _, i := range self.listening_sockets[:] {
-			self.deleteListeningSocketR(i)
-		}
-
-		for _, i := range self.connected_sockets[:] {
-			self.deleteConnectedSocketR(i)
-		}
-
-		set_stopped()
-	}()
-
-	var timeout_cleanup = worker01_interval_cleanup
-
-	for true {
-		log.Println("ARPC worker iteration")
-		if self.stop_flag {
-			break
-		}
-
-		if timeout_cleanup <= 0 {
-			timeout_cleanup = worker01_interval_cleanup
-
-			for _, x := range self.calls[:] {
-				if x.TTL <= 0 {
-					self.deleteCallR(x)
-				} else {
-					x.TTL -= worker01_interval_cleanup
-				}
-			}
-
-			for _, x := range self.buffers[:] {
-				if x.TTL <= 0 {
-					self.deleteBufferR(x)
-				} else {
-					x.TTL -= worker01_interval_cleanup
-				}
-			}
-
-			for _, x := range self.transmissions[:] {
-				if x.TTL <= 0 {
-					self.deleteTransmissionR(x)
-				} else {
-					x.TTL -= worker01_interval_cleanup
-				}
-			}
-
-			for _, x := range self.listening_sockets[:] {
-				if x.TTL <= 0 {
-					self.deleteListeningSocketR(x)
-				} else {
-					x.TTL -= worker01_interval_cleanup
-				}
-			}
-
-			for _, x := range self.connected_sockets[:] {
-				if x.TTL <= 0 {
-					self.deleteConnectedSocketR(x)
-				} else {
-					x.TTL -= worker01_interval_cleanup
-				}
-			}
-
-		} else {
-			timeout_cleanup -= time.Second
-		}
-
-		time.Sleep(time.Second)
-	}
-}
-
+// removes record, stops it's lease, calls it's Deleted() hook and informs
+// remote node. does nothing if record already deleted
 func (self *ARPCNodeCtlBasic) deleteCallR(
 	obj *ARPCNodeCtlBasicCallR,
+	reason ARPCObjectRemoveReason,
 ) {
-	self.calls_mtx.Lock()
-	defer self.calls_mtx.Unlock()
+	found := false
 
+	self.calls_mtx.Lock()
 	for i := len(self.calls) - 1; i != -1; i-- {
 		if self.calls[i] == obj {
 			self.calls = append(self.calls[:i], self.calls[i+1:]...)
+			found = true
 		}
 	}
+	self.calls_mtx.Unlock()
+
+	if found {
+		obj.Lease.stop()
+		obj.Deleted(reason)
+		self.notifyObjectRemoved(ARPCObjectTypeCall, obj.CallId, reason)
+	}
 }
 
 func (self *ARPCNodeCtlBasic) deleteBufferR(
 	obj *ARPCNodeCtlBasicBufferR,
+	reason ARPCObjectRemoveReason,
 ) {
-	self.buffers_mtx.Lock()
-	defer self.buffers_mtx.Unlock()
+	found := false
 
+	self.buffers_mtx.Lock()
 	for i := len(self.buffers) - 1; i != -1; i-- {
 		if self.buffers[i] == obj {
 			self.buffers = append(
 				self.buffers[:i],
 				self.buffers[i+1:]...,
 			)
+			found = true
 		}
 	}
+	self.buffers_mtx.Unlock()
+
+	if found {
+		obj.Lease.stop()
+		obj.Deleted(reason)
+		self.notifyObjectRemoved(ARPCObjectTypeBuffer, obj.BufferId, reason)
+	}
 }
 
 func (self *ARPCNodeCtlBasic) deleteTransmissionR(
 	obj *ARPCNodeCtlBasicTransmissionR,
+	reason ARPCObjectRemoveReason,
 ) {
-	self.transmissions_mtx.Lock()
-	defer self.transmissions_mtx.Unlock()
+	found := false
 
+	self.transmissions_mtx.Lock()
 	for i := len(self.transmissions) - 1; i != -1; i-- {
 		if self.transmissions[i] == obj {
 			self.transmissions = append(
 				self.transmissions[:i],
 				self.transmissions[i+1:]...,
 			)
+			found = true
 		}
 	}
+	self.transmissions_mtx.Unlock()
+
+	if found {
+		obj.Lease.stop()
+		obj.Deleted(reason)
+		self.notifyObjectRemoved(
+			ARPCObjectTypeTransmission,
+			obj.TransmissionId,
+			reason,
+		)
+	}
 }
 
 func (self *ARPCNodeCtlBasic) deleteListeningSocketR(
 	obj *ARPCNodeCtlBasicListeningSocketR,
+	reason ARPCObjectRemoveReason,
 ) {
-	self.listening_sockets_mtx.Lock()
-	defer self.listening_sockets_mtx.Unlock()
+	found := false
 
+	self.listening_sockets_mtx.Lock()
 	for i := len(self.listening_sockets) - 1; i != -1; i-- {
 		if self.listening_sockets[i] == obj {
 			self.listening_sockets = append(
 				self.listening_sockets[:i],
 				self.listening_sockets[i+1:]...,
 			)
+			found = true
 		}
 	}
+	self.listening_sockets_mtx.Unlock()
+
+	if found {
+		obj.Lease.stop()
+		obj.Deleted(reason)
+		self.notifyObjectRemoved(
+			ARPCObjectTypeListeningSocket,
+			obj.ListeningSocketId,
+			reason,
+		)
+	}
 }
 
 func (self *ARPCNodeCtlBasic) deleteConnectedSocketR(
 	obj *ARPCNodeCtlBasicConnectedSocketR,
-
+	reason ARPCObjectRemoveReason,
 ) {
-	self.connected_sockets_mtx.Lock()
-	defer self.connected_sockets_mtx.Unlock()
+	found := false
 
+	self.connected_sockets_mtx.Lock()
 	for i := len(self.connected_sockets) - 1; i != -1; i-- {
 		if self.connected_sockets[i] == obj {
 			self.connected_sockets = append(
 				self.connected_sockets[:i],
 				self.connected_sockets[i+1:]...,
 			)
+			found = true
 		}
 	}
+	self.connected_sockets_mtx.Unlock()
+
+	if found {
+		obj.Lease.stop()
+		obj.Deleted(reason)
+		self.notifyObjectRemoved(
+			ARPCObjectTypeConnectedSocket,
+			obj.ConnectedSocketId,
+			reason,
+		)
+	}
 }
 
-// Call and Reply essentially the same,
-// but Call has reply_to_id field set to nil, and Reply doesn't use
-// 'name' field
-
-func (self *ARPCNodeCtlBasic) Call(
-	name string,
-	args []*ARPCCallArg,
+// records of call args are released when call is deleted: record is
+// deleted, when last call owning it is deleted
 
-	unhandled bool,
-	response_handler *ARPCNodeCtlBasicCallResHandler, // TODO: ?
-) (ret_any *gouuidtools.UUID, ret_err error) {
+func (self *ARPCNodeCtlBasic) releaseBufferR(obj *ARPCNodeCtlBasicBufferR) {
+	self.buffers_mtx.Lock()
+	obj.owners--
+	last := obj.owners == 0
+	self.buffers_mtx.Unlock()
 
-	call_id, err := self.call_id_r.GenUUID()
-	if err != nil {
-		return
+	if last {
+		self.deleteBufferR(obj, ARPCObjectRemoveReasonClosed)
 	}
+}
 
-	err = self.saveCall(
-		call_id,
-		nil,
-		name,
-		args,
-		false,
-		response_handler,
-	)
+func (self *ARPCNodeCtlBasic) releaseTransmissionR(
+	obj *ARPCNodeCtlBasicTransmissionR,
+) {
+	self.transmissions_mtx.Lock()
+	obj.owners--
+	last := obj.owners == 0
+	self.transmissions_mtx.Unlock()
 
-	if err != nil {
-		return nil, err
+	if last {
+		self.deleteTransmissionR(obj, ARPCObjectRemoveReasonClosed)
 	}
+}
 
-	err = self.node.NewCall(
-		call_id,
-		nil,
-	)
+func (self *ARPCNodeCtlBasic) releaseListeningSocketR(
+	obj *ARPCNodeCtlBasicListeningSocketR,
+) {
+	self.listening_sockets_mtx.Lock()
+	obj.owners--
+	last := obj.owners == 0
+	self.listening_sockets_mtx.Unlock()
 
-	if err != nil {
-		return nil, err
+	if last {
+		self.deleteListeningSocketR(obj, ARPCObjectRemoveReasonClosed)
 	}
+}
 
-	return call_id, nil
+func (self *ARPCNodeCtlBasic) releaseConnectedSocketR(
+	obj *ARPCNodeCtlBasicConnectedSocketR,
+) {
+	self.connected_sockets_mtx.Lock()
+	obj.owners--
+	last := obj.owners == 0
+	self.connected_sockets_mtx.Unlock()
+
+	if last {
+		self.deleteConnectedSocketR(obj, ARPCObjectRemoveReasonClosed)
+	}
 }
 
-func (self *ARPCNodeCtlBasic) Reply(
-	reply_to_id *gouuidtools.UUID,
-	args ...*ARPCCallArg,
-) (
-	err error,
+// informs remote node about deleted object. errors are ignored:
+// remote node will receive "not found" on next access anyway
+func (self *ARPCNodeCtlBasic) notifyObjectRemoved(
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+	reason ARPCObjectRemoveReason,
 ) {
-	call_id, err := self.call_id_r.GenUUID()
-	if err != nil {
+	if reason == ARPCObjectRemoveReasonExpired {
+		self.rememberExpired(object_id)
+	}
+
+	node := self.node
+	if self.stop_flag || node == nil || node.IsClosed() {
 		return
 	}
 
-	err = self.saveCall(
-		call_id,
-		reply_to_id,
-		"",
-		args,
-		true,
-		nil,
-	)
+	err := node.ObjectRemoved(object_type, object_id, reason)
+	if err != nil && self.debug {
+		self.DebugPrintln("ObjectRemoved notification error:", err)
+	}
+}
 
-	if err != nil {
-		return err
+// remembers id of object, deleted by expiry. only last
+// ARPC_EXPIRED_IDS_MAX ids are kept
+func (self *ARPCNodeCtlBasic) rememberExpired(object_id *gouuidtools.UUID) {
+	self.expired_ids_mtx.Lock()
+	defer self.expired_ids_mtx.Unlock()
+
+	key := object_id.Format()
+	if _, ok := self.expired_ids[key]; ok {
+		return
 	}
 
-	err = self.node.NewCall(
-		call_id,
-		reply_to_id,
-	)
+	self.expired_ids[key] = struct{}{}
+	self.expired_ids_order = append(self.expired_ids_order, key)
 
-	if err != nil {
-		return err
+	if len(self.expired_ids_order) > ARPC_EXPIRED_IDS_MAX {
+		delete(self.expired_ids, self.expired_ids_order[0])
+		self.expired_ids_order = self.expired_ids_order[1:]
 	}
+}
 
-	return nil
+// error for object, which isn't registered: ARPCErrorCodeExpired if it
+// was deleted by expiry recently, else ARPCErrorCodeNotFound
+func (self *ARPCNodeCtlBasic) notFoundError(
+	object_id *gouuidtools.UUID,
+	what string,
+) *ARPCError {
+	self.expired_ids_mtx.Lock()
+	_, expired := self.expired_ids[object_id.Format()]
+	self.expired_ids_mtx.Unlock()
+
+	if expired {
+		return NewARPCError(ARPCErrorCodeExpired, what+" expired", nil)
+	}
+
+	return NewARPCError(ARPCErrorCodeNotFound, what+" not found", nil)
 }
 
-func (self *ARPCNodeCtlBasic) saveCall(
-	call_id *gouuidtools.UUID,
-	reply_to_id *gouuidtools.UUID,
+// deletes all the records
+func (self *ARPCNodeCtlBasic) deleteAll() {
+	self.calls_mtx.Lock()
+	calls := append([]*ARPCNodeCtlBasicCallR(nil), self.calls...)
+	self.calls_mtx.Unlock()
 
-	name string,
-	args []*ARPCCallArg,
+	self.buffers_mtx.Lock()
+	buffers := append([]*ARPCNodeCtlBasicBufferR(nil), self.buffers...)
+	self.buffers_mtx.Unlock()
 
-	// those 3 parama are for new calls, not for replys (you can't reply on reply)
-	unhandled bool,
-	response_handler *ARPCNodeCtlBasicCallResHandler,
+	self.transmissions_mtx.Lock()
+	transmissions := append(
+		[]*ARPCNodeCtlBasicTransmissionR(nil),
+		self.transmissions...,
+	)
+	self.transmissions_mtx.Unlock()
 
-	// note: here TTL is more appropriate name, because call object removed
-	// by timeout, not immediatly after response.
-	// also response have TTL, so it can be rechecked
-	// note: maybe this parameter isn't needed at all
-	// TTL time.Duration,
-) error {
+	self.listening_sockets_mtx.Lock()
+	listening_sockets := append(
+		[]*ARPCNodeCtlBasicListeningSocketR(nil),
+		self.listening_sockets...,
+	)
+	self.listening_sockets_mtx.Unlock()
 
-	if (name != "" && reply_to_id != nil) ||
-		(name == "" && reply_to_id == nil) {
-		return errors.New(
-			"only 'name' or only 'reply_to_id' must be set",
-		)
+	self.connected_sockets_mtx.Lock()
+	connected_sockets := append(
+		[]*ARPCNodeCtlBasicConnectedSocketR(nil),
+		self.connected_sockets...,
+	)
+	self.connected_sockets_mtx.Unlock()
+
+	for _, i := range calls {
+		self.deleteCallR(i, ARPCObjectRemoveReasonClosed)
 	}
 
-	var err error
+	for _, i := range buffers {
+		self.deleteBufferR(i, ARPCObjectRemoveReasonClosed)
+	}
 
-	for _, i := range args {
-		if i == nil {
-			return errors.New("nil is not acceptable amont args")
-		}
+	for _, i := range transmissions {
+		self.deleteTransmissionR(i, ARPCObjectRemoveReasonClosed)
+	}
 
-		if err = i.IsValidError(); err != nil {
-			return err
-		}
+	for _, i := range listening_sockets {
+		self.deleteListeningSocketR(i, ARPCObjectRemoveReasonClosed)
 	}
 
-	buffer_w := make([]*ARPCNodeCtlBasicBufferR, 0)
-	transmission_w := make([]*ARPCNodeCtlBasicTransmissionR, 0)
-	listening_socket_w := make([]*ARPCNodeCtlBasicListeningSocketR, 0)
-	connected_socket_w := make([]*ARPCNodeCtlBasicConnectedSocketR, 0)
+	for _, i := range connected_sockets {
+		self.deleteConnectedSocketR(i, ARPCObjectRemoveReasonClosed)
+	}
+}
 
-	for _, i := range args {
-		if i.Buffer != nil {
-			uuid := i.Buffer.Id
-			if uuid == nil || uuid.IsNil() {
-				uuid, err = self.buffer_id_r.GenUUID()
-				if err != nil {
-					return err
-				}
-			}
+func (self *ARPCNodeCtlBasic) getCallR(
+	call_id *gouuidtools.UUID,
+) *ARPCNodeCtlBasicCallR {
+	self.calls_mtx.Lock()
+	defer self.calls_mtx.Unlock()
 
-			b := &ARPCNodeCtlBasicBufferR{
-				Ctl:      self,
-				BufferId: uuid,
-				Buffer:   i.Buffer.Payload,
-				TTL:      TTL_CONST_10MIN,
-			}
+	for _, i := range self.calls {
+		if uuidsEqual(i.CallId, call_id) {
+			i.Lease.Touch()
+			return i
+		}
+	}
 
-			buffer_w = append(buffer_w, b)
+	return nil
+}
+
+func (self *ARPCNodeCtlBasic) getBufferR(
+	buffer_id *gouuidtools.UUID,
+) *ARPCNodeCtlBasicBufferR {
+	self.buffers_mtx.Lock()
+	defer self.buffers_mtx.Unlock()
+
+	for _, i := range self.buffers {
+		if uuidsEqual(i.BufferId, buffer_id) {
+			i.Lease.Touch()
+			return i
 		}
 	}
 
-	for _, i := range args {
-		if i.Transmission != nil {
-			uuid := i.Transmission.Id
-			if uuid == nil || uuid.IsNil() {
-				uuid, err = self.transmission_id_r.GenUUID()
-				if err != nil {
-					return err
-				}
-			}
+	return nil
+}
 
-			b := &ARPCNodeCtlBasicTransmissionR{
-				Ctl:            self,
-				TransmissionId: uuid,
-				Transmission:   i.Transmission.Payload,
-				TTL:            TTL_CONST_10MIN,
-			}
+func (self *ARPCNodeCtlBasic) getListeningSocketR(
+	listening_socket_id *gouuidtools.UUID,
+) *ARPCNodeCtlBasicListeningSocketR {
+	self.listening_sockets_mtx.Lock()
+	defer self.listening_sockets_mtx.Unlock()
 
-			transmission_w = append(transmission_w, b)
+	for _, i := range self.listening_sockets {
+		if uuidsEqual(i.ListeningSocketId, listening_socket_id) {
+			i.Lease.Touch()
+			return i
+		}
+	}
+
+	return nil
+}
+
+func (self *ARPCNodeCtlBasic) getConnectedSocketR(
+	connected_socket_id *gouuidtools.UUID,
+) *ARPCNodeCtlBasicConnectedSocketR {
+	self.connected_sockets_mtx.Lock()
+	defer self.connected_sockets_mtx.Unlock()
+
+	for _, i := range self.connected_sockets {
+		if uuidsEqual(i.ConnectedSocketId, connected_socket_id) {
+			i.Lease.Touch()
+			return i
+		}
+	}
+
+	return nil
+}
+
+func (self *ARPCNodeCtlBasic) getTransmissionR(
+	transmission_id *gouuidtools.UUID,
+) *ARPCNodeCtlBasicTransmissionR {
+	self.transmissions_mtx.Lock()
+	defer self.transmissions_mtx.Unlock()
+
+	for _, i := range self.transmissions {
+		if uuidsEqual(i.TransmissionId, transmission_id) {
+			i.Lease.Touch()
+			return i
+		}
+	}
+
+	return nil
+}
+
+// registers buffer. if buffer_id is nil - new id is generated
+func (self *ARPCNodeCtlBasic) addBufferR(
+	buffer_id *gouuidtools.UUID,
+	buffer ARPCBufferI,
+) (*ARPCNodeCtlBasicBufferR, error) {
+
+	b, err := self.newBufferR(buffer_id, buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	self.buffers_mtx.Lock()
+	defer self.buffers_mtx.Unlock()
+
+	self.buffers = append(self.buffers, b)
+	b.startLease()
+	b.watchFinished()
+
+	return b, nil
+}
+
+// creates record of buffer, but doesn't register it. if buffer_id is
+// nil - new id is generated
+func (self *ARPCNodeCtlBasic) newBufferR(
+	buffer_id *gouuidtools.UUID,
+	buffer ARPCBufferI,
+) (*ARPCNodeCtlBasicBufferR, error) {
+
+	if buffer == nil {
+		return nil, errors.New("buffer is nil")
+	}
+
+	var err error
+
+	if buffer_id == nil || buffer_id.IsNil() {
+		buffer_id, err = self.buffer_id_r.GenUUID()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	b := &ARPCNodeCtlBasicBufferR{
+		Ctl:      self,
+		BufferId: buffer_id,
+		Buffer:   buffer,
+		Lease:    self.newLease(ARPCObjectTypeBuffer, 0, false),
+	}
+
+	return b, nil
+}
+
+// publishes values as new finished object buffer. used to reply to
+// *GetList() calls
+func (self *ARPCNodeCtlBasic) publishList(
+	title string,
+	values []any,
+) (*gouuidtools.UUID, error) {
+
+	buffer := NewARPCBufferMemObject(title, "")
+	for _, i := range values {
+		_, err := buffer.Append(i)
+		if err != nil {
+			return nil, err
+		}
+	}
+	buffer.SetFinished()
+
+	b, err := self.addBufferR(nil, buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.BufferId, nil
+}
+
+// registers buffer and informs remote node about it. if buffer_id is
+// nil - new id is generated
+func (self *ARPCNodeCtlBasic) PublishBuffer(
+	buffer_id *gouuidtools.UUID,
+	buffer ARPCBufferI,
+) (*gouuidtools.UUID, error) {
+	node := self.node
+	if node == nil {
+		return nil, errors.New("node not set")
+	}
+
+	b, err := self.addBufferR(buffer_id, buffer)
+	if err != nil {
+		return nil, err
+	}
+
+	err = node.NewBuffer(b.BufferId)
+	if err != nil {
+		return nil, err
+	}
+
+	return b.BufferId, nil
+}
+
+// Call and Reply essentially the same,
+// but Call has reply_to_id field set to nil, and Reply doesn't use
+// 'name' field
+
+// if unhandled is true, response_handler is ignored and reply is passed to
+// OnUnhandledResultCB. response_handler.OnTimeout called if reply not
+// received while call is alive
+func (self *ARPCNodeCtlBasic) Call(
+	name string,
+	args []*ARPCCallArg,
+
+	unhandled bool,
+	response_handler *ARPCNodeCtlBasicCallResHandler,
+) (ret_any *gouuidtools.UUID, ret_err error) {
+
+	call_id, err := self.call_id_r.GenUUID()
+	if err != nil {
+		return nil, err
+	}
+
+	if unhandled {
+		response_handler = nil
+	}
+
+	err = self.saveCall(
+		call_id,
+		nil,
+		name,
+		args,
+		0,
+		"",
+		nil,
+		unhandled,
+		response_handler,
+	)
+
+	if err != nil {
+		return nil, err
+	}
+
+	if response_handler != nil {
+		w := &xARPCNodeCtlBasicCallResHandlerWrapper{
+			handler: response_handler,
+			id:      call_id,
+		}
+		w.expiry = self.expiry.newEntry(
+			func() {
+				self.handlerTimedOut(w)
+			},
+		)
+
+		self.handlers_mtx.Lock()
+		self.handlers = append(self.handlers, w)
+		self.handlers_mtx.Unlock()
+
+		self.expiry.reset(w.expiry, self.DefaultCallTTL)
+	}
+
+	err = self.node.NewCall(
+		call_id,
+		nil,
+	)
+
+	if err != nil {
+		self.popHandler(call_id)
+		return nil, err
+	}
+
+	return call_id, nil
+}
+
+func (self *ARPCNodeCtlBasic) Reply(
+	reply_to_id *gouuidtools.UUID,
+	args ...*ARPCCallArg,
+) (
+	err error,
+) {
+	return self.ReplyWithCodeAndMsg(reply_to_id, 0, "", args...)
+}
+
+// same as Reply, but also sets error code and message.
+// Reply() is same as ReplyWithCodeAndMsg(reply_to_id, 0, "", args...)
+func (self *ARPCNodeCtlBasic) ReplyWithCodeAndMsg(
+	reply_to_id *gouuidtools.UUID,
+	reply_err_code uint,
+	reply_err_msg string,
+	args ...*ARPCCallArg,
+) (
+	err error,
+) {
+	return self.reply(reply_to_id, reply_err_code, reply_err_msg, nil, args)
+}
+
+// same as Reply, but code, message and data of error are taken from
+// ToARPCError(reply_err), so remote side gets it as same ARPCError
+// (see ARPCCall.ReplyError())
+func (self *ARPCNodeCtlBasic) ReplyWithError(
+	reply_to_id *gouuidtools.UUID,
+	reply_err error,
+	args ...*ARPCCallArg,
+) (
+	err error,
+) {
+	if reply_err == nil {
+		return self.reply(reply_to_id, 0, "", nil, args)
+	}
+
+	arpc_err := ToARPCError(reply_err)
+
+	return self.reply(
+		reply_to_id,
+		uint(arpc_err.Code),
+		arpc_err.Message,
+		arpc_err.Data,
+		args,
+	)
+}
+
+func (self *ARPCNodeCtlBasic) reply(
+	reply_to_id *gouuidtools.UUID,
+	reply_err_code uint,
+	reply_err_msg string,
+	reply_err_data any,
+	args []*ARPCCallArg,
+) (
+	err error,
+) {
+	call_id, err := self.call_id_r.GenUUID()
+	if err != nil {
+		return
+	}
+
+	err = self.saveCall(
+		call_id,
+		reply_to_id,
+		"",
+		args,
+		reply_err_code,
+		reply_err_msg,
+		reply_err_data,
+		true,
+		nil,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	err = self.node.NewCall(
+		call_id,
+		reply_to_id,
+	)
+
+	if err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// removes handler waiting for reply to call_id and returns it.
+// nil if no such handler
+func (self *ARPCNodeCtlBasic) popHandler(
+	call_id *gouuidtools.UUID,
+) *ARPCNodeCtlBasicCallResHandler {
+	self.handlers_mtx.Lock()
+	defer self.handlers_mtx.Unlock()
+
+	for i := len(self.handlers) - 1; i != -1; i-- {
+		if uuidsEqual(self.handlers[i].id, call_id) {
+			ret := self.handlers[i]
+			self.handlers = append(self.handlers[:i], self.handlers[i+1:]...)
+			self.expiry.remove(ret.expiry)
+			return ret.handler
+		}
+	}
+
+	return nil
+}
+
+// removes handler and calls it's OnTimeout()
+func (self *ARPCNodeCtlBasic) handlerTimedOut(
+	w *xARPCNodeCtlBasicCallResHandlerWrapper,
+) {
+	found := false
+
+	self.handlers_mtx.Lock()
+	for i := len(self.handlers) - 1; i != -1; i-- {
+		if self.handlers[i] == w {
+			self.handlers = append(self.handlers[:i], self.handlers[i+1:]...)
+			found = true
+		}
+	}
+	self.handlers_mtx.Unlock()
+
+	if found && w.handler.OnTimeout != nil {
+		go w.handler.OnTimeout()
+	}
+}
+
+// calls OnClose() for all awaiting handlers and removes them
+func (self *ARPCNodeCtlBasic) closeHandlers() {
+	self.handlers_mtx.Lock()
+	handlers := self.handlers
+	self.handlers = nil
+	self.handlers_mtx.Unlock()
+
+	for _, i := range handlers {
+		self.expiry.remove(i.expiry)
+		if i.handler.OnClose != nil {
+			go i.handler.OnClose()
+		}
+	}
+}
+
+func (self *ARPCNodeCtlBasic) saveCall(
+	call_id *gouuidtools.UUID,
+	reply_to_id *gouuidtools.UUID,
+
+	name string,
+	args []*ARPCCallArg,
+
+	// those are for replys
+	reply_err_code uint,
+	reply_err_msg string,
+	reply_err_data any,
+
+	// those 3 parama are for new calls, not for replys (you can't reply on reply)
+	unhandled bool,
+	response_handler *ARPCNodeCtlBasicCallResHandler,
+
+	// note: here TTL is more appropriate name, because call object removed
+	// by timeout, not immediatly after response.
+	// also response have TTL, so it can be rechecked
+	// note: maybe this parameter isn't needed at all
+	// TTL time.Duration,
+) error {
+
+	if (name != "" && reply_to_id != nil) ||
+		(name == "" && reply_to_id == nil) {
+		return errors.New(
+			"only 'name' or only 'reply_to_id' must be set",
+		)
+	}
+
+	var err error
+
+	for _, i := range args {
+		if i == nil {
+			return errors.New("nil is not acceptable amont args")
+		}
+
+		if err = i.IsValidError(); err != nil {
+			return err
+		}
+	}
+
+	self.calls_mtx.Lock()
+	defer self.calls_mtx.Unlock()
+
+	self.buffers_mtx.Lock()
+	defer self.buffers_mtx.Unlock()
+
+	self.transmissions_mtx.Lock()
+	defer self.transmissions_mtx.Unlock()
+
+	self.listening_sockets_mtx.Lock()
+	defer self.listening_sockets_mtx.Unlock()
+
+	self.connected_sockets_mtx.Lock()
+	defer self.connected_sockets_mtx.Unlock()
+
+	call := &ARPCNodeCtlBasicCallR{
+		Ctl:       self,
+		CallId:    call_id,
+		ReplyToId: reply_to_id,
+		Name:      name,
+		Args:      args,
+
+		ReplyErrCode: reply_err_code,
+		ReplyErrMsg:  reply_err_msg,
+		ReplyErrData: reply_err_data,
+
+		ResponseHandler: response_handler,
+		Lease:           self.newLease(ARPCObjectTypeCall, 0, false),
+	}
+
+	// args with ids of already registered objects reuse their records.
+	// args with other ids must have payload: records without payload
+	// can't serve requests
+	// call owns records of it's args, except pinned ones and ones
+	// registered by application
+
+	buffer_w := make([]*ARPCNodeCtlBasicBufferR, 0)
+	transmission_w := make([]*ARPCNodeCtlBasicTransmissionR, 0)
+	listening_socket_w := make([]*ARPCNodeCtlBasicListeningSocketR, 0)
+	connected_socket_w := make([]*ARPCNodeCtlBasicConnectedSocketR, 0)
+
+	for _, i := range args {
+		if i.Buffer != nil {
+			uuid := i.Buffer.Id
+			if uuid == nil || uuid.IsNil() {
+				uuid, err = self.buffer_id_r.GenUUID()
+				if err != nil {
+					return err
+				}
+				i.Buffer.Id = uuid
+			} else if b := self.getBufferR(uuid); b != nil {
+				if b.owners != 0 {
+					call.owned_buffers = append(call.owned_buffers, b)
+				}
+				continue
+			}
+
+			if i.Buffer.Payload == nil {
+				return errors.New(
+					"buffer arg with unknown id have no payload",
+				)
+			}
+
+			b := &ARPCNodeCtlBasicBufferR{
+				Ctl:      self,
+				BufferId: uuid,
+				Buffer:   i.Buffer.Payload,
+				Lease: self.newLease(
+					ARPCObjectTypeBuffer,
+					i.Buffer.TTL,
+					i.Buffer.Pinned,
+				),
+			}
+
+			buffer_w = append(buffer_w, b)
+			if !i.Buffer.Pinned {
+				call.owned_buffers = append(call.owned_buffers, b)
+			}
+		}
+	}
+
+	for _, i := range args {
+		if i.Transmission != nil {
+			uuid := i.Transmission.Id
+			if uuid == nil || uuid.IsNil() {
+				uuid, err = self.transmission_id_r.GenUUID()
+				if err != nil {
+					return err
+				}
+				i.Transmission.Id = uuid
+			} else if b := self.getTransmissionR(uuid); b != nil {
+				if b.owners != 0 {
+					call.owned_transmissions =
+						append(call.owned_transmissions, b)
+				}
+				continue
+			}
+
+			if i.Transmission.Payload == nil {
+				return errors.New(
+					"transmission arg with unknown id have no payload",
+				)
+			}
+
+			b := &ARPCNodeCtlBasicTransmissionR{
+				Ctl:            self,
+				TransmissionId: uuid,
+				Transmission:   i.Transmission.Payload,
+				Lease: self.newLease(
+					ARPCObjectTypeTransmission,
+					i.Transmission.TTL,
+					i.Transmission.Pinned,
+				),
+			}
+
+			transmission_w = append(transmission_w, b)
+			if !i.Transmission.Pinned {
+				call.owned_transmissions = append(call.owned_transmissions, b)
+			}
 		}
 	}
 
@@ -511,16 +1115,37 @@ func (self *ARPCNodeCtlBasic) saveCall(
 				if err != nil {
 					return err
 				}
+				i.ListeningSocket.Id = uuid
+			} else if b := self.getListeningSocketR(uuid); b != nil {
+				if b.owners != 0 {
+					call.owned_listening_sockets =
+						append(call.owned_listening_sockets, b)
+				}
+				continue
+			}
+
+			if i.ListeningSocket.Payload == nil {
+				return errors.New(
+					"listening socket arg with unknown id have no payload",
+				)
 			}
 
 			b := &ARPCNodeCtlBasicListeningSocketR{
 				Ctl:               self,
 				ListeningSocketId: uuid,
 				ListeningSocket:   i.ListeningSocket.Payload,
-				TTL:               TTL_CONST_10MIN,
+				Lease: self.newLease(
+					ARPCObjectTypeListeningSocket,
+					i.ListeningSocket.TTL,
+					i.ListeningSocket.Pinned,
+				),
 			}
 
 			listening_socket_w = append(listening_socket_w, b)
+			if !i.ListeningSocket.Pinned {
+				call.owned_listening_sockets =
+					append(call.owned_listening_sockets, b)
+			}
 		}
 	}
 
@@ -532,44 +1157,62 @@ func (self *ARPCNodeCtlBasic) saveCall(
 				if err != nil {
 					return err
 				}
+				i.ConnectedSocket.Id = uuid
+			} else if b := self.getConnectedSocketR(uuid); b != nil {
+				if b.owners != 0 {
+					call.owned_connected_sockets =
+						append(call.owned_connected_sockets, b)
+				}
+				continue
+			}
+
+			if i.ConnectedSocket.Payload == nil {
+				return errors.New(
+					"connected socket arg with unknown id have no payload",
+				)
 			}
 
 			b := &ARPCNodeCtlBasicConnectedSocketR{
 				Ctl:               self,
 				ConnectedSocketId: uuid,
 				ConnectedSocket:   i.ConnectedSocket.Payload,
-				TTL:               TTL_CONST_10MIN,
+				Lease: self.newLease(
+					ARPCObjectTypeConnectedSocket,
+					i.ConnectedSocket.TTL,
+					i.ConnectedSocket.Pinned,
+				),
 			}
 
 			connected_socket_w = append(connected_socket_w, b)
+			if !i.ConnectedSocket.Pinned {
+				call.owned_connected_sockets =
+					append(call.owned_connected_sockets, b)
+			}
 		}
 	}
 
-	call := &ARPCNodeCtlBasicCallR{
-		Ctl:       self,
-		CallId:    call_id,
-		ReplyToId: reply_to_id,
-		Name:      name,
-		Args:      args,
-
-		ResponseHandler: response_handler,
-		TTL:             TTL_CONST_10MIN,
+	for _, i := range transmission_w {
+		if i.Transmission == nil {
+			continue
+		}
+		err = self.registerTransmissionBuffers(i)
+		if err != nil {
+			return err
+		}
 	}
 
-	self.calls_mtx.Lock()
-	defer self.calls_mtx.Unlock()
-
-	self.buffers_mtx.Lock()
-	defer self.buffers_mtx.Unlock()
-
-	self.transmissions_mtx.Lock()
-	defer self.transmissions_mtx.Unlock()
-
-	self.listening_sockets_mtx.Lock()
-	defer self.listening_sockets_mtx.Unlock()
-
-	self.connected_sockets_mtx.Lock()
-	defer self.connected_sockets_mtx.Unlock()
+	for _, i := range call.owned_buffers {
+		i.owners++
+	}
+	for _, i := range call.owned_transmissions {
+		i.owners++
+	}
+	for _, i := range call.owned_listening_sockets {
+		i.owners++
+	}
+	for _, i := range call.owned_connected_sockets {
+		i.owners++
+	}
 
 	self.calls = append(self.calls, call)
 
@@ -590,6 +1233,22 @@ func (self *ARPCNodeCtlBasic) saveCall(
 		connected_socket_w...,
 	)
 
+	call.startLease()
+	for _, i := range buffer_w {
+		i.startLease()
+		i.watchFinished()
+	}
+	for _, i := range transmission_w {
+		i.startLease()
+		i.watchFinished()
+	}
+	for _, i := range listening_socket_w {
+		i.startLease()
+	}
+	for _, i := range connected_socket_w {
+		i.startLease()
+	}
+
 	return nil
 }
 
@@ -600,44 +1259,320 @@ func (self *ARPCNodeCtlBasic) SimpleRequest(msg *gojsonrpc2.Message) (error, err
 	return self.OnSimpleRequestCB(msg)
 }
 
+// returned net.Conn works through remote node's Socket* methods
 func (self *ARPCNodeCtlBasic) SocketGetConn(
 	connected_socket_id *gouuidtools.UUID,
 ) (net.Conn, error) {
-	return nil, nil
+	node := self.node
+	if node == nil {
+		return nil, errors.New("node not set")
+	}
+
+	if connected_socket_id == nil || connected_socket_id.IsNil() {
+		return nil, errors.New("invalid connected_socket_id")
+	}
+
+	return NewARPCRemoteConn(
+		node,
+		connected_socket_id,
+		self.ResponseTimeout,
+	), nil
+}
+
+// opens remote listening socket and returns resulting connection
+func (self *ARPCNodeCtlBasic) SocketDial(
+	listening_socket_id *gouuidtools.UUID,
+) (net.Conn, error) {
+	node := self.node
+	if node == nil {
+		return nil, errors.New("node not set")
+	}
+
+	connected_socket_id, timedout, closed, result_err, err :=
+		node.SocketOpen(listening_socket_id, self.ResponseTimeout)
+	err = remoteResultToError(timedout, closed, result_err, err)
+	if err != nil {
+		return nil, err
+	}
+
+	return self.SocketGetConn(connected_socket_id)
+}
+
+// registers listening socket, so remote node could open it. if
+// listening_socket_id is nil - new id is generated.
+// remote node isn't informed: send id to it as call argument, use
+// PublishSocket() or it can be found using SocketGetList().
+// listening_socket is closed, when controller is closed. on expiry it's
+// record is deleted, but listening_socket is left to application
+func (self *ARPCNodeCtlBasic) SocketListen(
+	listening_socket_id *gouuidtools.UUID,
+	listening_socket ARPCListeningSocketI,
+) (*gouuidtools.UUID, error) {
+	if listening_socket == nil {
+		return nil, errors.New("listening_socket is nil")
+	}
+
+	var err error
+
+	if listening_socket_id == nil || listening_socket_id.IsNil() {
+		listening_socket_id, err = self.listening_socket_id_r.GenUUID()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	ls := &ARPCNodeCtlBasicListeningSocketR{
+		Ctl:               self,
+		ListeningSocketId: listening_socket_id,
+		ListeningSocket:   listening_socket,
+		Lease:             self.newLease(ARPCObjectTypeListeningSocket, 0, false),
+	}
+
+	self.listening_sockets_mtx.Lock()
+	defer self.listening_sockets_mtx.Unlock()
+
+	self.listening_sockets = append(self.listening_sockets, ls)
+	ls.startLease()
+
+	return listening_socket_id, nil
+}
+
+// same as SocketListen(), but also informs remote node about new socket
+func (self *ARPCNodeCtlBasic) PublishSocket(
+	listening_socket_id *gouuidtools.UUID,
+	listening_socket ARPCListeningSocketI,
+) (*gouuidtools.UUID, error) {
+	node := self.node
+	if node == nil {
+		return nil, errors.New("node not set")
+	}
+
+	listening_socket_id, err := self.SocketListen(
+		listening_socket_id,
+		listening_socket,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	err = node.NewSocket(listening_socket_id)
+	if err != nil {
+		return nil, err
+	}
+
+	return listening_socket_id, nil
 }
 
+// fetches new call (or reply) from remote node. calls are passed to
+// OnCallCB, replies - to respective response handlers or to
+// OnUnhandledResultCB.
 func (self *ARPCNodeCtlBasic) NewCall(
 	call_id *gouuidtools.UUID,
 	response_on *gouuidtools.UUID,
-) {
-	log.Println("controller got NewCall()")
+) (error, error) {
+	if self.debug {
+		self.DebugPrintln("controller got NewCall()", call_id, response_on)
+	}
+
+	call, err := self.fetchRemoteCall(call_id)
+	if err != nil {
+		return nil, err
+	}
+
+	if response_on == nil {
+		if call.Name == "" {
+			return errors.New("call without name and response_on"), nil
+		}
+
+		if self.OnCallCB == nil {
+			return nil, errors.New("OnCallCB undefined")
+		}
+
+		return self.OnCallCB(call)
+	}
+
+	h := self.popHandler(response_on)
+	if h != nil {
+		if h.OnResponse != nil {
+			h.OnResponse(call)
+		}
+		return nil, nil
+	}
+
+	if self.OnUnhandledResultCB != nil {
+		self.OnUnhandledResultCB(call)
+	}
+
+	return nil, nil
+}
+
+// gets call info and all it's args from remote node
+func (self *ARPCNodeCtlBasic) fetchRemoteCall(
+	call_id *gouuidtools.UUID,
+) (*ARPCCall, error) {
+
+	node := self.node
+	if node == nil {
+		return nil, errors.New("node not set")
+	}
+
+	info, timedout, closed, result_err, err :=
+		node.CallGetInfo(call_id, self.ResponseTimeout)
+	err = remoteResultToError(timedout, closed, result_err, err)
+	if err != nil {
+		return nil, err
+	}
+
+	count, timedout, closed, result_err, err :=
+		node.CallGetArgCount(call_id, self.ResponseTimeout)
+	err = remoteResultToError(timedout, closed, result_err, err)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &ARPCCall{
+		Name:         info.Name,
+		ReplyToId:    info.ReplyToId,
+		CallId:       call_id,
+		ReplyErrCode: info.ReplyErrCode,
+		ReplyErrMsg:  info.ReplyErrMsg,
+		ReplyErrData: info.ReplyErrData,
+	}
+
+	if count == 0 {
+		return ret, nil
+	}
+
+	arg_infos, timedout, closed, result_err, err :=
+		node.CallGetArgValues(call_id, 0, count-1, self.ResponseTimeout)
+	err = remoteResultToError(timedout, closed, result_err, err)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, i := range arg_infos {
+		arg, err := i.GenARPCCallArg()
+		if err != nil {
+			return nil, err
+		}
+		ret.Args = append(ret.Args, arg)
+	}
+
+	return ret, nil
+}
+
+// joins errors of remote call results into one
+func remoteResultToError(
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) error {
+	switch {
+	case err != nil:
+		return err
+	case result_err != nil:
+		return result_err
+	case timedout:
+		return errors.New("timedout")
+	case closed:
+		return errors.New("closed")
+	}
+	return nil
 }
 
 func (self *ARPCNodeCtlBasic) NewBuffer(
 	buffer_id *gouuidtools.UUID,
 ) {
+	if self.OnNewBufferCB != nil {
+		self.OnNewBufferCB(buffer_id)
+	}
 }
 
 func (self *ARPCNodeCtlBasic) BufferUpdated(
 	buffer_id *gouuidtools.UUID,
 ) {
+	if self.OnBufferUpdatedCB != nil {
+		self.OnBufferUpdatedCB(buffer_id)
+	}
 }
 
 func (self *ARPCNodeCtlBasic) NewTransmission(
 	tarnsmission_id *gouuidtools.UUID,
 ) {
+	if self.OnNewTransmissionCB != nil {
+		self.OnNewTransmissionCB(tarnsmission_id)
+	}
 }
 
 func (self *ARPCNodeCtlBasic) NewSocket(
 	listening_socket_id *gouuidtools.UUID,
 ) {
+	if self.OnNewSocketCB != nil {
+		self.OnNewSocketCB(listening_socket_id)
+	}
+}
+
+func (self *ARPCNodeCtlBasic) ObjectFinished(
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+) {
+	if self.OnObjectFinishedCB != nil {
+		self.OnObjectFinishedCB(object_type, object_id)
+	}
+}
+
+func (self *ARPCNodeCtlBasic) ObjectRemoved(
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+	reason ARPCObjectRemoveReason,
+) {
+	if object_type == ARPCObjectTypeConnectedSocket {
+		self.streamConnRemoved(object_id)
+	}
+
+	if self.OnObjectRemovedCB != nil {
+		self.OnObjectRemovedCB(object_type, object_id, reason)
+	}
 }
 
 func (self *ARPCNodeCtlBasic) CallGetList() (
 	buffer_id *gouuidtools.UUID,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, nil, errors.New("not implimented")
+	self.calls_mtx.Lock()
+	values := make([]any, 0, len(self.calls))
+	for _, i := range self.calls {
+		values = append(
+			values,
+			&ARPCCallShortItem{
+				CallId:  i.CallId,
+				ReplyTo: i.ReplyToId,
+			},
+		)
+	}
+	self.calls_mtx.Unlock()
+
+	buffer_id, err := self.publishList("calls", values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return buffer_id, nil, nil
+}
+
+func (self *ARPCNodeCtlBasic) CallGetInfo(
+	call_id *gouuidtools.UUID,
+) (
+	info *ARPCCallForJSON,
+	err_processing_not_internal, err_processing_internal error,
+) {
+	call := self.getCallR(call_id)
+	if call == nil {
+		return nil, self.notFoundError(call_id, "call"), nil
+	}
+
+	return call.GenARPCCallForJSON(), nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) CallGetName(
@@ -646,7 +1581,12 @@ func (self *ARPCNodeCtlBasic) CallGetName(
 	name string,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return "", nil, errors.New("not implimented")
+	call := self.getCallR(call_id)
+	if call == nil {
+		return "", self.notFoundError(call_id, "call"), nil
+	}
+
+	return call.Name, nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) CallGetArgCount(
@@ -655,7 +1595,12 @@ func (self *ARPCNodeCtlBasic) CallGetArgCount(
 	res int,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return 0, nil, errors.New("not implimented")
+	call := self.getCallR(call_id)
+	if call == nil {
+		return 0, self.notFoundError(call_id, "call"), nil
+	}
+
+	return len(call.Args), nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) CallGetArgValues(
@@ -665,7 +1610,30 @@ func (self *ARPCNodeCtlBasic) CallGetArgValues(
 	res []*ARPCArgInfo,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, nil, errors.New("not implimented")
+	call := self.getCallR(call_id)
+	if call == nil {
+		return nil, self.notFoundError(call_id, "call"), nil
+	}
+
+	if first < 0 || last < first || last >= len(call.Args) {
+		return nil, NewARPCError(
+			ARPCErrorCodeInvalidArgument,
+			"invalid first/last values",
+			nil,
+		), nil
+	}
+
+	res = make([]*ARPCArgInfo, 0, last-first+1)
+
+	for _, i := range call.Args[first : last+1] {
+		info, err := i.GenARPCArgInfo()
+		if err != nil {
+			return nil, nil, err
+		}
+		res = append(res, info)
+	}
+
+	return res, nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) CallClose(
@@ -673,16 +1641,36 @@ func (self *ARPCNodeCtlBasic) CallClose(
 ) (
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, errors.New("not implimented")
+	call := self.getCallR(call_id)
+	if call == nil {
+		return self.notFoundError(call_id, "call"), nil
+	}
+
+	self.deleteCallR(call, ARPCObjectRemoveReasonClosed)
+
+	return nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) BufferGetInfo(
 	buffer_id *gouuidtools.UUID,
 ) (
-	info *ARPCCallForJSON,
+	info *ARPCBufferInfo,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, nil, errors.New("not implimented")
+	buffer := self.getBufferR(buffer_id)
+	if buffer == nil {
+		return nil, self.notFoundError(buffer_id, "buffer"), nil
+	}
+
+	info = buffer.Buffer.GetInfo()
+	if info == nil {
+		return nil, nil, errors.New("buffer returned nil info")
+	}
+
+	info_copy := *info
+	info_copy.Id = buffer_id
+
+	return &info_copy, nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) BufferGetItemsCount(
@@ -691,7 +1679,12 @@ func (self *ARPCNodeCtlBasic) BufferGetItemsCount(
 	count int,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return 0, nil, errors.New("not implimented")
+	buffer := self.getBufferR(buffer_id)
+	if buffer == nil {
+		return 0, self.notFoundError(buffer_id, "buffer"), nil
+	}
+
+	return buffer.Buffer.ItemCount(), nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) BufferGetItemsIds(
@@ -701,45 +1694,176 @@ func (self *ARPCNodeCtlBasic) BufferGetItemsIds(
 	ids []string,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, nil, errors.New("not implimented")
+	buffer := self.getBufferR(buffer_id)
+	if buffer == nil {
+		return nil, self.notFoundError(buffer_id, "buffer"), nil
+	}
+
+	first, err_processing_not_internal, err_processing_internal :=
+		bufferResolveItemSpecifier(buffer.Buffer, first_spec, false)
+	if err_processing_not_internal != nil || err_processing_internal != nil {
+		return
+	}
+
+	last, err_processing_not_internal, err_processing_internal :=
+		bufferResolveItemSpecifier(buffer.Buffer, last_spec, true)
+	if err_processing_not_internal != nil || err_processing_internal != nil {
+		return
+	}
+
+	ids = make([]string, 0)
+
+	for i := first; i <= last && len(ids) != ARPC_BUFFER_ITEMS_IDS_MAX_COUNT; i++ {
+		item, found, err := bufferGetItemMetaByIndex(buffer.Buffer, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		if found {
+			ids = append(ids, item.ItemId)
+		}
+	}
+
+	return ids, nil, nil
+}
+
+func (self *ARPCNodeCtlBasic) BufferGetItemsTimesByIds(
+	buffer_id *gouuidtools.UUID,
+	ids []string,
+) (
+	times []time.Time,
+	err_processing_not_internal, err_processing_internal error,
+) {
+	buffer := self.getBufferR(buffer_id)
+	if buffer == nil {
+		return nil, self.notFoundError(buffer_id, "buffer"), nil
+	}
+
+	if len(ids) > ARPC_BUFFER_ITEMS_IDS_MAX_COUNT {
+		return nil, bufferTooManyIdsError(), nil
+	}
+
+	times = make([]time.Time, 0, len(ids))
+
+	for _, i := range ids {
+		item, found, err := bufferGetItemMeta(buffer.Buffer, i)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !found {
+			return nil, ARPCErrorf(ARPCErrorCodeNotFound, "item not found: %s", i), nil
+		}
+
+		times = append(times, item.ItemTime)
+	}
+
+	return times, nil, nil
 }
 
-func (self *ARPCNodeCtlBasic) BufferGetItemsTimesByIds(
+// items are returned in order of ids. binary values of result are
+// limited by ARPC_BUFFER_BINARY_MAX_SLICE_SIZE bytes in total, so
+// result may be shorter than ids (but has at least one item): rest is
+// requested by caller
+func (self *ARPCNodeCtlBasic) BufferGetItemsByIds(
 	buffer_id *gouuidtools.UUID,
 	ids []string,
 ) (
-	times []time.Time,
+	buffer_items []*ARPCBufferItem,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, nil, errors.New("not implimented")
+	buffer := self.getBufferR(buffer_id)
+	if buffer == nil {
+		return nil, self.notFoundError(buffer_id, "buffer"), nil
+	}
+
+	if len(ids) > ARPC_BUFFER_ITEMS_IDS_MAX_COUNT {
+		return nil, bufferTooManyIdsError(), nil
+	}
+
+	buffer_items = make([]*ARPCBufferItem, 0, len(ids))
+	size := 0
+
+	for _, i := range ids {
+		item, found, err := buffer.Buffer.GetItem(i)
+		if err != nil {
+			return nil, nil, err
+		}
+		if !found {
+			return nil, ARPCErrorf(ARPCErrorCodeNotFound, "item not found: %s", i), nil
+		}
+
+		if b, ok := item.Value.([]byte); ok {
+			size += len(b)
+			if size > ARPC_BUFFER_BINARY_MAX_SLICE_SIZE && len(buffer_items) != 0 {
+				break
+			}
+		}
+
+		item_copy := *item
+		item_copy.BufferId = buffer_id
+
+		buffer_items = append(buffer_items, &item_copy)
+	}
+
+	return buffer_items, nil, nil
 }
 
-func (self *ARPCNodeCtlBasic) BufferGetItemsByIds(
+func bufferTooManyIdsError() error {
+	return ARPCErrorf(
+		ARPCErrorCodeInvalidArgument,
+		"more than %d ids requested",
+		ARPC_BUFFER_ITEMS_IDS_MAX_COUNT,
+	)
+}
+
+func (self *ARPCNodeCtlBasic) BufferGetItemsFirstTime(
 	buffer_id *gouuidtools.UUID,
-	ids []string,
 ) (
-	buffer_items []*ARPCBufferItem,
+	time_ time.Time,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, nil, errors.New("not implimented")
+	return self.bufferGetItemTime(buffer_id, false)
 }
 
-func (self *ARPCNodeCtlBasic) BufferGetItemsFirstTime(
+func (self *ARPCNodeCtlBasic) BufferGetItemsLastTime(
 	buffer_id *gouuidtools.UUID,
 ) (
 	time_ time.Time,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return time.Time{}, nil, errors.New("not implimented")
+	return self.bufferGetItemTime(buffer_id, true)
 }
 
-func (self *ARPCNodeCtlBasic) BufferGetItemsLastTime(
+func (self *ARPCNodeCtlBasic) bufferGetItemTime(
 	buffer_id *gouuidtools.UUID,
+	last bool,
 ) (
 	time_ time.Time,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return time.Time{}, nil, errors.New("not implimented")
+	buffer := self.getBufferR(buffer_id)
+	if buffer == nil {
+		return time.Time{}, self.notFoundError(buffer_id, "buffer"), nil
+	}
+
+	count := buffer.Buffer.ItemCount()
+	if count == 0 {
+		return time.Time{}, NewARPCError(ARPCErrorCodeNotFound, "buffer is empty", nil), nil
+	}
+
+	index := 0
+	if last {
+		index = count - 1
+	}
+
+	item, found, err := bufferGetItemMetaByIndex(buffer.Buffer, index)
+	if err != nil {
+		return time.Time{}, nil, err
+	}
+	if !found {
+		return time.Time{}, nil, errors.New("buffer item not found by index")
+	}
+
+	return item.ItemTime, nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) BufferSubscribeOnUpdatesNotification(
@@ -747,7 +1871,30 @@ func (self *ARPCNodeCtlBasic) BufferSubscribeOnUpdatesNotification(
 ) (
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, errors.New("not implimented")
+	buffer := self.getBufferR(buffer_id)
+	if buffer == nil {
+		return self.notFoundError(buffer_id, "buffer"), nil
+	}
+
+	node := self.node
+	if node == nil {
+		return nil, errors.New("node not set")
+	}
+
+	self.buffers_mtx.Lock()
+	defer self.buffers_mtx.Unlock()
+
+	if buffer.Subscribed {
+		return nil, nil
+	}
+
+	buffer.Subscribed = true
+
+	if x, ok := buffer.Buffer.(ARPCBufferUpdatesNotifierI); ok {
+		x.SubscribeNodeOnUpdates(node, buffer_id)
+	}
+
+	return nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) BufferUnsubscribeFromUpdatesNotification(
@@ -755,7 +1902,37 @@ func (self *ARPCNodeCtlBasic) BufferUnsubscribeFromUpdatesNotification(
 ) (
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, errors.New("not implimented")
+	buffer := self.getBufferR(buffer_id)
+	if buffer == nil {
+		return self.notFoundError(buffer_id, "buffer"), nil
+	}
+
+	self.buffers_mtx.Lock()
+	defer self.buffers_mtx.Unlock()
+
+	self.bufferUnsubscribe(buffer)
+
+	return nil, nil
+}
+
+// buffers_mtx must be locked by caller
+func (self *ARPCNodeCtlBasic) bufferUnsubscribe(
+	buffer *ARPCNodeCtlBasicBufferR,
+) {
+	if !buffer.Subscribed {
+		return
+	}
+
+	buffer.Subscribed = false
+
+	node := self.node
+	if node == nil {
+		return
+	}
+
+	if x, ok := buffer.Buffer.(ARPCBufferUpdatesNotifierI); ok {
+		x.UnsubscribeNodeFromUpdates(node, buffer.BufferId)
+	}
 }
 
 func (self *ARPCNodeCtlBasic) BufferGetIsSubscribedOnUpdatesNotification(
@@ -764,14 +1941,199 @@ func (self *ARPCNodeCtlBasic) BufferGetIsSubscribedOnUpdatesNotification(
 	r bool,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return false, nil, errors.New("not implimented")
+	buffer := self.getBufferR(buffer_id)
+	if buffer == nil {
+		return false, self.notFoundError(buffer_id, "buffer"), nil
+	}
+
+	self.buffers_mtx.Lock()
+	defer self.buffers_mtx.Unlock()
+
+	return buffer.Subscribed, nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) BufferGetListSubscribedUpdatesNotifications() (
 	buffer_id *gouuidtools.UUID,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, nil, errors.New("not implimented")
+	self.buffers_mtx.Lock()
+	values := make([]any, 0)
+	for _, i := range self.buffers {
+		if i.Subscribed {
+			values = append(values, i.BufferId.Format())
+		}
+	}
+	self.buffers_mtx.Unlock()
+
+	buffer_id, err := self.publishList("subscribed buffers", values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return buffer_id, nil, nil
+}
+
+// buffers which doesn't implement ARPCBufferUpdatesNotifierI can't inform
+// remote node about own changes. call this on such buffer changes, so
+// controller could notify remote node, if it's subscribed on buffer's
+// updates. if buffer became finished, remote node is informed about it
+// (ARPCFinishNotifierI buffers do it by themselves)
+func (self *ARPCNodeCtlBasic) BufferChanged(
+	buffer_id *gouuidtools.UUID,
+) error {
+	buffer := self.getBufferR(buffer_id)
+	if buffer == nil {
+		return self.notFoundError(buffer_id, "buffer")
+	}
+
+	err := self.bufferCheckFinished(buffer)
+	if err != nil {
+		return err
+	}
+
+	self.buffers_mtx.Lock()
+	subscribed := buffer.Subscribed
+	self.buffers_mtx.Unlock()
+
+	if !subscribed {
+		return nil
+	}
+
+	if _, ok := buffer.Buffer.(ARPCBufferUpdatesNotifierI); ok {
+		// buffer notifies node by itself
+		return nil
+	}
+
+	node := self.node
+	if node == nil {
+		return errors.New("node not set")
+	}
+
+	return node.BufferUpdated(buffer_id)
+}
+
+// informs remote node, if buffer became finished. only first change is
+// reported
+func (self *ARPCNodeCtlBasic) bufferCheckFinished(
+	buffer *ARPCNodeCtlBasicBufferR,
+) error {
+	if !buffer.Buffer.GetInfo().Finished {
+		return nil
+	}
+
+	self.buffers_mtx.Lock()
+	reported := buffer.finished
+	buffer.finished = true
+	self.buffers_mtx.Unlock()
+
+	if reported {
+		return nil
+	}
+
+	return self.notifyObjectFinished(ARPCObjectTypeBuffer, buffer.BufferId)
+}
+
+// see bufferCheckFinished()
+func (self *ARPCNodeCtlBasic) transmissionCheckFinished(
+	tr *ARPCNodeCtlBasicTransmissionR,
+) error {
+	if tr.Transmission.GetInfo().State != ARPCTransmissionStateFinished {
+		return nil
+	}
+
+	self.transmissions_mtx.Lock()
+	reported := tr.finished
+	tr.finished = true
+	self.transmissions_mtx.Unlock()
+
+	if reported {
+		return nil
+	}
+
+	return self.notifyObjectFinished(
+		ARPCObjectTypeTransmission,
+		tr.TransmissionId,
+	)
+}
+
+func (self *ARPCNodeCtlBasic) notifyObjectFinished(
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+) error {
+	node := self.node
+	if self.stop_flag || node == nil || node.IsClosed() {
+		return nil
+	}
+
+	err := node.ObjectFinished(object_type, object_id)
+	if err != nil && self.debug {
+		self.DebugPrintln("ObjectFinished notification error:", err)
+	}
+
+	return err
+}
+
+// informs remote node, that buffer or transmission is finished. buffers
+// and transmissions, implementing ARPCFinishNotifierI, are reported
+// automatically, so this is needed only for others
+func (self *ARPCNodeCtlBasic) NotifyFinished(
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+) error {
+	switch object_type {
+	default:
+		return ARPCErrorf(
+			ARPCErrorCodeInvalidArgument,
+			"objects of type %q can't be finished",
+			object_type,
+		)
+	case ARPCObjectTypeBuffer:
+		if self.getBufferR(object_id) == nil {
+			return self.notFoundError(object_id, "buffer")
+		}
+	case ARPCObjectTypeTransmission:
+		if self.getTransmissionR(object_id) == nil {
+			return self.notFoundError(object_id, "transmission")
+		}
+	}
+
+	node := self.node
+	if node == nil {
+		return errors.New("node not set")
+	}
+
+	return node.ObjectFinished(object_type, object_id)
+}
+
+// returns error (not internal) if buffer isn't in binary mode
+func (self *ARPCNodeCtlBasic) getBinaryBuffer(
+	buffer_id *gouuidtools.UUID,
+) (
+	buffer ARPCBufferBinaryI,
+	err_processing_not_internal, err_processing_internal error,
+) {
+	buffer_r := self.getBufferR(buffer_id)
+	if buffer_r == nil {
+		return nil, self.notFoundError(buffer_id, "buffer"), nil
+	}
+
+	info := buffer_r.Buffer.GetInfo()
+	if info == nil || info.Mode != ARPCBufferModeBinary {
+		return nil, NewARPCError(
+			ARPCErrorCodeWrongBufferMode,
+			"buffer is not in binary mode",
+			nil,
+		), nil
+	}
+
+	buffer, ok := buffer_r.Buffer.(ARPCBufferBinaryI)
+	if !ok {
+		return nil, nil, errors.New(
+			"binary mode buffer doesn't implement ARPCBufferBinaryI",
+		)
+	}
+
+	return buffer, nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) BufferBinaryGetSize(
@@ -780,7 +2142,18 @@ func (self *ARPCNodeCtlBasic) BufferBinaryGetSize(
 	size int,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return 0, nil, errors.New("not implimented")
+	buffer, err_processing_not_internal, err_processing_internal :=
+		self.getBinaryBuffer(buffer_id)
+	if err_processing_not_internal != nil || err_processing_internal != nil {
+		return
+	}
+
+	size, err := buffer.BinarySize()
+	if err != nil {
+		return 0, nil, err
+	}
+
+	return size, nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) BufferBinaryGetSlice(
@@ -790,14 +2163,58 @@ func (self *ARPCNodeCtlBasic) BufferBinaryGetSlice(
 	data []byte,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, nil, errors.New("not implimented")
+	buffer, err_processing_not_internal, err_processing_internal :=
+		self.getBinaryBuffer(buffer_id)
+	if err_processing_not_internal != nil || err_processing_internal != nil {
+		return
+	}
+
+	size, err := buffer.BinarySize()
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if start_index < 0 || end_index < start_index || end_index > size {
+		return nil, NewARPCError(
+			ARPCErrorCodeInvalidArgument,
+			"invalid start_index/end_index values",
+			nil,
+		), nil
+	}
+
+	if end_index-start_index > ARPC_BUFFER_BINARY_MAX_SLICE_SIZE {
+		return nil, ARPCErrorf(
+			ARPCErrorCodeInvalidArgument,
+			"slice is longer than %d bytes",
+			ARPC_BUFFER_BINARY_MAX_SLICE_SIZE,
+		), nil
+	}
+
+	data, err = buffer.BinarySlice(start_index, end_index)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return data, nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) TransmissionGetList() (
 	buffer_id *gouuidtools.UUID,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, nil, errors.New("not implimented")
+	self.transmissions_mtx.Lock()
+	values := make([]any, 0, len(self.transmissions))
+	for _, i := range self.transmissions {
+		values = append(values, i.TransmissionId.Format())
+	}
+	self.transmissions_mtx.Unlock()
+
+	buffer_id, err := self.publishList("transmissions", values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return buffer_id, nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) TransmissionGetInfo(
@@ -806,23 +2223,229 @@ func (self *ARPCNodeCtlBasic) TransmissionGetInfo(
 	info *ARPCTransmissionInfo,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, nil, errors.New("not implimented")
+	tr := self.getTransmissionR(transmission_id)
+	if tr == nil {
+		return nil, self.notFoundError(transmission_id, "transmission"), nil
+	}
+
+	if tr.Transmission == nil {
+		return nil, nil, errors.New("transmission have no payload")
+	}
+
+	// buffers may be added to transmission after it's registration
+	err := self.registerTransmissionBuffers(tr)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	info = tr.Transmission.GetInfo()
+	if info == nil {
+		return nil, nil, errors.New("transmission returned nil info")
+	}
+
+	info_copy := *info
+	info_copy.Id = transmission_id
+	info_copy.BufferIds = make([]*gouuidtools.UUID, 0)
+	info_copy.BufferNames = make([]string, 0)
+
+	for _, i := range tr.Transmission.GetBuffers() {
+		info_copy.BufferIds = append(info_copy.BufferIds, i.Id)
+		info_copy.BufferNames = append(info_copy.BufferNames, i.Name)
+	}
+
+	return &info_copy, nil, nil
+}
+
+// registers transmission with all it's buffers and informs remote node
+// about it. if transmission_id is nil - new id is generated.
+func (self *ARPCNodeCtlBasic) PublishTransmission(
+	transmission_id *gouuidtools.UUID,
+	transmission ARPCTransmissionI,
+) (*gouuidtools.UUID, error) {
+
+	if transmission == nil {
+		return nil, errors.New("transmission is nil")
+	}
+
+	node := self.node
+	if node == nil {
+		return nil, errors.New("node not set")
+	}
+
+	var err error
+
+	if transmission_id == nil || transmission_id.IsNil() {
+		transmission_id, err = self.transmission_id_r.GenUUID()
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	tr := &ARPCNodeCtlBasicTransmissionR{
+		Ctl:            self,
+		TransmissionId: transmission_id,
+		Transmission:   transmission,
+		Lease:          self.newLease(ARPCObjectTypeTransmission, 0, false),
+	}
+
+	err = self.registerTransmissionBuffers(tr)
+	if err != nil {
+		return nil, err
+	}
+
+	self.transmissions_mtx.Lock()
+	self.transmissions = append(self.transmissions, tr)
+	tr.startLease()
+	tr.watchFinished()
+	self.transmissions_mtx.Unlock()
+
+	err = node.NewTransmission(transmission_id)
+	if err != nil {
+		return nil, err
+	}
+
+	return transmission_id, nil
+}
+
+// registers transmission's buffers, which aren't registered yet.
+// those are owned by transmission record. on error nothing is registered
+func (self *ARPCNodeCtlBasic) registerTransmissionBuffers(
+	tr *ARPCNodeCtlBasicTransmissionR,
+) error {
+	// same order as in saveCall()
+	self.buffers_mtx.Lock()
+	defer self.buffers_mtx.Unlock()
+
+	self.transmissions_mtx.Lock()
+	defer self.transmissions_mtx.Unlock()
+
+	tr_buffers := make([]*ARPCTransmissionBuffer, 0)
+	buffer_w := make([]*ARPCNodeCtlBasicBufferR, 0)
+
+	for _, i := range tr.Transmission.GetBuffers() {
+		if i.Id != nil && !i.Id.IsNil() && self.getBufferR(i.Id) != nil {
+			continue
+		}
+
+		b, err := self.newBufferR(i.Id, i.Payload)
+		if err != nil {
+			return err
+		}
+
+		tr_buffers = append(tr_buffers, i)
+		buffer_w = append(buffer_w, b)
+	}
+
+	for j, b := range buffer_w {
+		self.buffers = append(self.buffers, b)
+		b.owners++
+		b.startLease()
+		b.watchFinished()
+		tr.owned_buffers = append(tr.owned_buffers, b)
+
+		tr_buffers[j].Id = b.BufferId
+	}
+
+	return nil
+}
+
+func (self *ARPCNodeCtlBasic) SocketGetList() (
+	buffer_id *gouuidtools.UUID,
+	err_processing_not_internal, err_processing_internal error,
+) {
+	self.listening_sockets_mtx.Lock()
+	values := make([]any, 0, len(self.listening_sockets))
+	for _, i := range self.listening_sockets {
+		values = append(values, i.ListeningSocketId.Format())
+	}
+	self.listening_sockets_mtx.Unlock()
+
+	buffer_id, err := self.publishList("listening sockets", values)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return buffer_id, nil, nil
+}
+
+func (self *ARPCNodeCtlBasic) SocketOpen(
+	listening_socket_id *gouuidtools.UUID,
+) (
+	connected_socket_id *gouuidtools.UUID,
+	err_processing_not_internal, err_processing_internal error,
+) {
+	ls := self.getListeningSocketR(listening_socket_id)
+	if ls == nil {
+		return nil, self.notFoundError(listening_socket_id, "listening socket"), nil
+	}
+
+	if ls.ListeningSocket == nil {
+		return nil, nil, errors.New("listening socket have no payload")
+	}
+
+	conn, err := ls.ListeningSocket.Open()
+	if err != nil {
+		return nil, err, nil
+	}
+
+	connected_socket_id, err = self.connected_socket_id_r.GenUUID()
+	if err != nil {
+		conn.Close()
+		return nil, nil, err
+	}
+
+	cs := &ARPCNodeCtlBasicConnectedSocketR{
+		Ctl:               self,
+		ConnectedSocketId: connected_socket_id,
+		ConnectedSocket:   conn,
+		Lease:             self.newLease(ARPCObjectTypeConnectedSocket, 0, false),
+		opened:            true,
+	}
+
+	self.connected_sockets_mtx.Lock()
+	self.connected_sockets = append(self.connected_sockets, cs)
+	cs.startLease()
+	self.connected_sockets_mtx.Unlock()
+
+	return connected_socket_id, nil, nil
 }
 
-func (self *ARPCNodeCtlBasic) SocketGetList() (
-	buffer_id *gouuidtools.UUID,
-	err_processing_not_internal, err_processing_internal error,
-) {
-	return nil, nil, errors.New("not implimented")
+// converts local socket error to error, which can be restored on remote
+// side by ARPCRemoteConn
+func socketErrorToRemote(err error) error {
+	if errors.Is(err, io.EOF) {
+		return NewARPCError(ARPCErrorCodeEOF, ARPC_SOCKET_ERR_MSG_EOF, nil)
+	}
+
+	if errors.Is(err, net.ErrClosed) {
+		return NewARPCError(ARPCErrorCodeClosed, ARPC_SOCKET_ERR_MSG_CLOSED, nil)
+	}
+
+	if x, ok := err.(net.Error); (ok && x.Timeout()) ||
+		errors.Is(err, os.ErrDeadlineExceeded) {
+		return NewARPCError(ARPCErrorCodeTimeout, ARPC_SOCKET_ERR_MSG_TIMEOUT, nil)
+	}
+
+	return err
 }
 
-func (self *ARPCNodeCtlBasic) SocketOpen(
-	listening_socket_id *gouuidtools.UUID,
-) (
+// returns record of connected socket, which have payload
+func (self *ARPCNodeCtlBasic) getConnectedSocket(
 	connected_socket_id *gouuidtools.UUID,
+) (
+	cs *ARPCNodeCtlBasicConnectedSocketR,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, nil, errors.New("not implimented")
+	cs = self.getConnectedSocketR(connected_socket_id)
+	if cs == nil {
+		return nil, self.notFoundError(connected_socket_id, "connected socket"), nil
+	}
+
+	if cs.ConnectedSocket == nil {
+		return nil, nil, errors.New("connected socket have no payload")
+	}
+
+	return cs, nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) SocketRead(
@@ -832,7 +2455,35 @@ func (self *ARPCNodeCtlBasic) SocketRead(
 	b []byte,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, nil, errors.New("not implimented")
+	cs, err_processing_not_internal, err_processing_internal :=
+		self.getConnectedSocket(connected_socket_id)
+	if err_processing_not_internal != nil || err_processing_internal != nil {
+		return
+	}
+
+	// size is chosen by remote node
+	if try_read_size > ARPC_REMOTE_CONN_MAX_READ_SIZE {
+		try_read_size = ARPC_REMOTE_CONN_MAX_READ_SIZE
+	}
+
+	b = make([]byte, try_read_size)
+
+	// read may block for long, while socket is idle
+	cs.Lease.enter()
+	n, err := cs.ConnectedSocket.Read(b)
+	cs.Lease.leave()
+
+	// data is returned even if error happened. error will be returned
+	// on next read
+	if n > 0 {
+		return b[:n], nil, nil
+	}
+
+	if err != nil {
+		return nil, socketErrorToRemote(err), nil
+	}
+
+	return b[:0], nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) SocketWrite(
@@ -842,7 +2493,18 @@ func (self *ARPCNodeCtlBasic) SocketWrite(
 	n int,
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return 0, nil, errors.New("not implimented")
+	cs, err_processing_not_internal, err_processing_internal :=
+		self.getConnectedSocket(connected_socket_id)
+	if err_processing_not_internal != nil || err_processing_internal != nil {
+		return
+	}
+
+	n, err := cs.ConnectedSocket.Write(b)
+	if err != nil && n == 0 {
+		return 0, socketErrorToRemote(err), nil
+	}
+
+	return n, nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) SocketClose(
@@ -850,7 +2512,19 @@ func (self *ARPCNodeCtlBasic) SocketClose(
 ) (
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, errors.New("not implimented")
+	cs := self.getConnectedSocketR(connected_socket_id)
+	if cs == nil {
+		return self.notFoundError(connected_socket_id, "connected socket"), nil
+	}
+
+	self.deleteConnectedSocketR(cs, ARPCObjectRemoveReasonClosed)
+
+	err := cs.closePayload()
+	if err != nil {
+		return socketErrorToRemote(err), nil
+	}
+
+	return nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) SocketSetDeadline(
@@ -859,7 +2533,18 @@ func (self *ARPCNodeCtlBasic) SocketSetDeadline(
 ) (
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, errors.New("not implimented")
+	cs, err_processing_not_internal, err_processing_internal :=
+		self.getConnectedSocket(connected_socket_id)
+	if err_processing_not_internal != nil || err_processing_internal != nil {
+		return
+	}
+
+	err := cs.ConnectedSocket.SetDeadline(t)
+	if err != nil {
+		return socketErrorToRemote(err), nil
+	}
+
+	return nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) SocketSetReadDeadline(
@@ -868,7 +2553,18 @@ func (self *ARPCNodeCtlBasic) SocketSetReadDeadline(
 ) (
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, errors.New("not implimented")
+	cs, err_processing_not_internal, err_processing_internal :=
+		self.getConnectedSocket(connected_socket_id)
+	if err_processing_not_internal != nil || err_processing_internal != nil {
+		return
+	}
+
+	err := cs.ConnectedSocket.SetReadDeadline(t)
+	if err != nil {
+		return socketErrorToRemote(err), nil
+	}
+
+	return nil, nil
 }
 
 func (self *ARPCNodeCtlBasic) SocketSetWriteDeadline(
@@ -877,7 +2573,277 @@ func (self *ARPCNodeCtlBasic) SocketSetWriteDeadline(
 ) (
 	err_processing_not_internal, err_processing_internal error,
 ) {
-	return nil, errors.New("not implimented")
+	cs, err_processing_not_internal, err_processing_internal :=
+		self.getConnectedSocket(connected_socket_id)
+	if err_processing_not_internal != nil || err_processing_internal != nil {
+		return
+	}
+
+	err := cs.ConnectedSocket.SetWriteDeadline(t)
+	if err != nil {
+		return socketErrorToRemote(err), nil
+	}
+
+	return nil, nil
+}
+
+// lease of record: record is deleted when lease expires.
+// pinned records never expire. lease is started, when record is
+// registered, and stopped, when record is deleted
+type ARPCNodeCtlBasicLease struct {
+	sched *xARPCExpiryScheduler
+
+	mtx     sync.Mutex
+	ttl     time.Duration
+	pinned  bool
+	entry   *xARPCExpiryEntry
+	stopped bool
+
+	// count of operations in progress (blocked socket reads, stream
+	// pumps). lease doesn't expire while it's not 0
+	busy int
+}
+
+func (self *ARPCNodeCtlBasicLease) start(on_expire func()) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.stopped || self.entry != nil {
+		return
+	}
+
+	self.entry = self.sched.newEntry(
+		func() {
+			self.expire(on_expire)
+		},
+	)
+
+	if !self.pinned {
+		self.sched.reset(self.entry, self.ttl)
+	}
+}
+
+// called by scheduler. lease may be renewed or pinned after scheduler
+// took it as due, so it's checked again. expired lease is stopped, so
+// it can't be renewed while on_expire runs
+func (self *ARPCNodeCtlBasicLease) expire(on_expire func()) {
+	self.mtx.Lock()
+	if self.stopped ||
+		self.pinned ||
+		self.busy != 0 ||
+		!self.sched.isDue(self.entry) {
+		self.mtx.Unlock()
+		return
+	}
+	self.stopped = true
+	self.mtx.Unlock()
+
+	on_expire()
+}
+
+func (self *ARPCNodeCtlBasicLease) stop() {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	self.stopped = true
+
+	if self.entry != nil {
+		self.sched.remove(self.entry)
+	}
+}
+
+// marks operation in progress. lease doesn't expire until leave()
+func (self *ARPCNodeCtlBasicLease) enter() {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.busy++
+}
+
+// ends operation started by enter() and renews lease
+func (self *ARPCNodeCtlBasicLease) leave() {
+	self.mtx.Lock()
+	self.busy--
+	self.mtx.Unlock()
+	self.Touch()
+}
+
+// renews lease. returns time left until expiry (0 for pinned)
+func (self *ARPCNodeCtlBasicLease) Touch() time.Duration {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.pinned {
+		return 0
+	}
+
+	if !self.stopped && self.entry != nil {
+		self.sched.reset(self.entry, self.ttl)
+	}
+
+	return self.ttl
+}
+
+func (self *ARPCNodeCtlBasicLease) GetTTL() time.Duration {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	return self.ttl
+}
+
+// sets TTL and renews lease
+func (self *ARPCNodeCtlBasicLease) SetTTL(ttl time.Duration) {
+	self.mtx.Lock()
+	self.ttl = ttl
+	self.mtx.Unlock()
+	self.Touch()
+}
+
+func (self *ARPCNodeCtlBasicLease) IsPinned() bool {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	return self.pinned
+}
+
+// unpinning renews lease
+func (self *ARPCNodeCtlBasicLease) SetPinned(pinned bool) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	self.pinned = pinned
+
+	if self.stopped || self.entry == nil {
+		return
+	}
+
+	if pinned {
+		self.sched.remove(self.entry)
+	} else {
+		self.sched.reset(self.entry, self.ttl)
+	}
+}
+
+// 0 or negative ttl - controller's default for object_type
+func (self *ARPCNodeCtlBasic) newLease(
+	object_type ARPCObjectType,
+	ttl time.Duration,
+	pinned bool,
+) *ARPCNodeCtlBasicLease {
+	if ttl <= 0 {
+		ttl = self.defaultTTL(object_type)
+	}
+	return &ARPCNodeCtlBasicLease{
+		sched:  self.expiry,
+		ttl:    ttl,
+		pinned: pinned,
+	}
+}
+
+func (self *ARPCNodeCtlBasic) defaultTTL(
+	object_type ARPCObjectType,
+) time.Duration {
+	var ret time.Duration
+	switch object_type {
+	case ARPCObjectTypeCall:
+		ret = self.DefaultCallTTL
+	case ARPCObjectTypeBuffer:
+		ret = self.DefaultBufferTTL
+	case ARPCObjectTypeTransmission:
+		ret = self.DefaultTransmissionTTL
+	case ARPCObjectTypeListeningSocket:
+		ret = self.DefaultListeningSocketTTL
+	case ARPCObjectTypeConnectedSocket:
+		ret = self.DefaultConnectedSocketTTL
+	}
+	if ret <= 0 {
+		ret = TTL_CONST_10MIN
+	}
+	return ret
+}
+
+// returns lease of object. accessing object renews it's lease
+func (self *ARPCNodeCtlBasic) getLease(
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+) (*ARPCNodeCtlBasicLease, error) {
+	var ret *ARPCNodeCtlBasicLease
+
+	switch object_type {
+	default:
+		return nil, ARPCErrorf(
+			ARPCErrorCodeInvalidArgument,
+			"invalid object type: %s", object_type,
+		)
+	case ARPCObjectTypeCall:
+		if x := self.getCallR(object_id); x != nil {
+			ret = x.Lease
+		}
+	case ARPCObjectTypeBuffer:
+		if x := self.getBufferR(object_id); x != nil {
+			ret = x.Lease
+		}
+	case ARPCObjectTypeTransmission:
+		if x := self.getTransmissionR(object_id); x != nil {
+			ret = x.Lease
+		}
+	case ARPCObjectTypeListeningSocket:
+		if x := self.getListeningSocketR(object_id); x != nil {
+			ret = x.Lease
+		}
+	case ARPCObjectTypeConnectedSocket:
+		if x := self.getConnectedSocketR(object_id); x != nil {
+			ret = x.Lease
+		}
+	}
+
+	if ret == nil {
+		return nil, self.notFoundError(object_id, string(object_type))
+	}
+
+	return ret, nil
+}
+
+// sets object's TTL and renews it's lease
+func (self *ARPCNodeCtlBasic) SetObjectTTL(
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+	ttl time.Duration,
+) error {
+	lease, err := self.getLease(object_type, object_id)
+	if err != nil {
+		return err
+	}
+	if ttl <= 0 {
+		ttl = self.defaultTTL(object_type)
+	}
+	lease.SetTTL(ttl)
+	return nil
+}
+
+// pinned objects never expire
+func (self *ARPCNodeCtlBasic) SetObjectPinned(
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+	pinned bool,
+) error {
+	lease, err := self.getLease(object_type, object_id)
+	if err != nil {
+		return err
+	}
+	lease.SetPinned(pinned)
+	return nil
+}
+
+func (self *ARPCNodeCtlBasic) Touch(
+	object_type ARPCObjectType,
+	object_id *gouuidtools.UUID,
+) (
+	ttl time.Duration,
+	err_processing_not_internal, err_processing_internal error,
+) {
+	lease, err := self.getLease(object_type, object_id)
+	if err != nil {
+		return 0, err, nil
+	}
+	return lease.Touch(), nil, nil
 }
 
 // 'R' at the end of next structs - stands for 'Record'
@@ -891,13 +2857,61 @@ type ARPCNodeCtlBasicCallR struct {
 	Name string
 	Args []*ARPCCallArg
 
+	ReplyErrCode uint
+	ReplyErrMsg  string
+	ReplyErrData any
+
 	Handled         bool
 	ResponseHandler *ARPCNodeCtlBasicCallResHandler
-	TTL             time.Duration
+
+	Lease *ARPCNodeCtlBasicLease
+
+	// records of args, owned by this call
+	owned_buffers           []*ARPCNodeCtlBasicBufferR
+	owned_transmissions     []*ARPCNodeCtlBasicTransmissionR
+	owned_listening_sockets []*ARPCNodeCtlBasicListeningSocketR
+	owned_connected_sockets []*ARPCNodeCtlBasicConnectedSocketR
+}
+
+func (self *ARPCNodeCtlBasicCallR) GenARPCCallForJSON() *ARPCCallForJSON {
+	return &ARPCCallForJSON{
+		CallId:       self.CallId,
+		ReplyToId:    self.ReplyToId,
+		Name:         self.Name,
+		ReplyErrCode: self.ReplyErrCode,
+		ReplyErrMsg:  self.ReplyErrMsg,
+		ReplyErrData: self.ReplyErrData,
+	}
 }
 
-func (self *ARPCNodeCtlBasicCallR) Deleted() {
-	// TODO: ?
+func (self *ARPCNodeCtlBasicCallR) startLease() {
+	self.Lease.start(
+		func() {
+			self.Ctl.deleteCallR(self, ARPCObjectRemoveReasonExpired)
+		},
+	)
+}
+
+// reply to deleted call can't be fetched, so awaiting handler is closed.
+// records of args are released
+func (self *ARPCNodeCtlBasicCallR) Deleted(reason ARPCObjectRemoveReason) {
+	h := self.Ctl.popHandler(self.CallId)
+	if h != nil && h.OnClose != nil {
+		go h.OnClose()
+	}
+
+	for _, i := range self.owned_buffers {
+		self.Ctl.releaseBufferR(i)
+	}
+	for _, i := range self.owned_transmissions {
+		self.Ctl.releaseTransmissionR(i)
+	}
+	for _, i := range self.owned_listening_sockets {
+		self.Ctl.releaseListeningSocketR(i)
+	}
+	for _, i := range self.owned_connected_sockets {
+		self.Ctl.releaseConnectedSocketR(i)
+	}
 }
 
 type ARPCNodeCtlBasicBufferR struct {
@@ -907,11 +2921,61 @@ type ARPCNodeCtlBasicBufferR struct {
 
 	Buffer ARPCBufferI
 
-	TTL time.Duration
+	// remote node is subscribed on buffer updates
+	Subscribed bool
+
+	Lease *ARPCNodeCtlBasicLease
+
+	// count of calls and transmissions owning this record.
+	// 0 - record is registered by application and isn't released by them
+	owners int
+
+	// remote node knows, that buffer is finished. see watchFinished()
+	finished         bool
+	unwatch_finished func()
+}
+
+func (self *ARPCNodeCtlBasicBufferR) startLease() {
+	self.Lease.start(
+		func() {
+			self.Ctl.deleteBufferR(self, ARPCObjectRemoveReasonExpired)
+		},
+	)
+}
+
+// buffers_mtx must be locked by caller. buffers, implementing
+// ARPCFinishNotifierI, inform remote node, when they are finished. else
+// application should call BufferChanged()
+func (self *ARPCNodeCtlBasicBufferR) watchFinished() {
+	if x, ok := self.Buffer.(ARPCFinishNotifierI); ok {
+		self.unwatch_finished = x.OnFinished(
+			func() {
+				self.Ctl.bufferCheckFinished(self)
+			},
+		)
+	}
+
+	// buffer, finished before registration, is reported as finished by
+	// BufferGetInfo()
+	if self.Buffer.GetInfo().Finished {
+		self.finished = true
+	}
 }
 
-func (self *ARPCNodeCtlBasicBufferR) Deleted() {
-	// TODO: ?
+// buffers which implement io.Closer (like ARPCBufferFile) are closed
+func (self *ARPCNodeCtlBasicBufferR) Deleted(reason ARPCObjectRemoveReason) {
+	self.Ctl.buffers_mtx.Lock()
+	self.Ctl.bufferUnsubscribe(self)
+	unwatch_finished := self.unwatch_finished
+	self.Ctl.buffers_mtx.Unlock()
+
+	if unwatch_finished != nil {
+		unwatch_finished()
+	}
+
+	if c, ok := self.Buffer.(io.Closer); ok {
+		c.Close()
+	}
 }
 
 type ARPCNodeCtlBasicTransmissionR struct {
@@ -921,11 +2985,54 @@ type ARPCNodeCtlBasicTransmissionR struct {
 
 	Transmission ARPCTransmissionI
 
-	TTL time.Duration
+	Lease *ARPCNodeCtlBasicLease
+
+	// count of calls owning this record. see ARPCNodeCtlBasicBufferR.owners
+	owners int
+
+	// records of transmission's buffers, owned by this transmission
+	owned_buffers []*ARPCNodeCtlBasicBufferR
+
+	// see ARPCNodeCtlBasicBufferR.finished
+	finished         bool
+	unwatch_finished func()
 }
 
-func (self *ARPCNodeCtlBasicTransmissionR) Deleted() {
-	// TODO: ?
+func (self *ARPCNodeCtlBasicTransmissionR) startLease() {
+	self.Lease.start(
+		func() {
+			self.Ctl.deleteTransmissionR(self, ARPCObjectRemoveReasonExpired)
+		},
+	)
+}
+
+// transmissions_mtx must be locked by caller
+func (self *ARPCNodeCtlBasicTransmissionR) watchFinished() {
+	if x, ok := self.Transmission.(ARPCFinishNotifierI); ok {
+		self.unwatch_finished = x.OnFinished(
+			func() {
+				self.Ctl.transmissionCheckFinished(self)
+			},
+		)
+	}
+
+	if self.Transmission.GetInfo().State == ARPCTransmissionStateFinished {
+		self.finished = true
+	}
+}
+
+func (self *ARPCNodeCtlBasicTransmissionR) Deleted(reason ARPCObjectRemoveReason) {
+	self.Ctl.transmissions_mtx.Lock()
+	unwatch_finished := self.unwatch_finished
+	self.Ctl.transmissions_mtx.Unlock()
+
+	if unwatch_finished != nil {
+		unwatch_finished()
+	}
+
+	for _, i := range self.owned_buffers {
+		self.Ctl.releaseBufferR(i)
+	}
 }
 
 type ARPCNodeCtlBasicListeningSocketR struct {
@@ -935,11 +3042,37 @@ type ARPCNodeCtlBasicListeningSocketR struct {
 
 	ListeningSocket ARPCListeningSocketI
 
-	TTL time.Duration
+	Lease *ARPCNodeCtlBasicLease
+
+	// count of calls owning this record. see ARPCNodeCtlBasicBufferR.owners
+	owners int
 }
 
-func (self *ARPCNodeCtlBasicListeningSocketR) Deleted() {
-	// TODO: ?
+func (self *ARPCNodeCtlBasicListeningSocketR) startLease() {
+	self.Lease.start(
+		func() {
+			self.Ctl.deleteListeningSocketR(self, ARPCObjectRemoveReasonExpired)
+		},
+	)
+}
+
+// closes listening socket (and so underlying net.Listener). socket,
+// registered by application, isn't closed on expiry: application may
+// still use it
+func (self *ARPCNodeCtlBasicListeningSocketR) Deleted(
+	reason ARPCObjectRemoveReason,
+) {
+	self.Ctl.listening_sockets_mtx.Lock()
+	by_app := self.owners == 0
+	self.Ctl.listening_sockets_mtx.Unlock()
+
+	if reason == ARPCObjectRemoveReasonExpired && by_app {
+		return
+	}
+
+	if self.ListeningSocket != nil {
+		self.ListeningSocket.Close()
+	}
 }
 
 type ARPCNodeCtlBasicConnectedSocketR struct {
@@ -949,17 +3082,67 @@ type ARPCNodeCtlBasicConnectedSocketR struct {
 
 	ConnectedSocket ARPCConnectedSocketI
 
-	TTL time.Duration
+	Lease *ARPCNodeCtlBasicLease
+
+	// count of calls owning this record. see ARPCNodeCtlBasicBufferR.owners
+	owners int
+
+	close_once sync.Once
+	close_err  error
+
+	// set while socket is streamed to remote node (see SocketStreamStart())
+	pump *xARPCNodeCtlBasicSocketPump
+
+	// connection is opened by controller in SocketOpen(), so it's closed
+	// with record in any case
+	opened bool
+}
+
+func (self *ARPCNodeCtlBasicConnectedSocketR) startLease() {
+	self.Lease.start(
+		func() {
+			self.Ctl.deleteConnectedSocketR(self, ARPCObjectRemoveReasonExpired)
+		},
+	)
+}
+
+// closes socket only once. result of first close is returned
+func (self *ARPCNodeCtlBasicConnectedSocketR) closePayload() error {
+	self.close_once.Do(
+		func() {
+			if self.ConnectedSocket != nil {
+				self.close_err = self.ConnectedSocket.Close()
+			}
+		},
+	)
+	return self.close_err
 }
 
-func (self *ARPCNodeCtlBasicConnectedSocketR) Deleted() {
+// socket, registered by application, isn't closed on expiry:
+// application may still use it
+func (self *ARPCNodeCtlBasicConnectedSocketR) Deleted(
+	reason ARPCObjectRemoveReason,
+) {
+	self.Ctl.connected_sockets_mtx.Lock()
+	pump := self.pump
+	by_app := self.owners == 0 && !self.opened
+	self.Ctl.connected_sockets_mtx.Unlock()
+
+	if pump != nil {
+		pump.stop()
+	}
+
+	if reason == ARPCObjectRemoveReasonExpired && by_app {
+		return
+	}
 
+	self.closePayload()
 }
 
 type xARPCNodeCtlBasicCallResHandlerWrapper struct {
 	handler *ARPCNodeCtlBasicCallResHandler
 	id      *gouuidtools.UUID
-	timeout time.Duration
+	expiry  *xARPCExpiryEntry
 }
 
 type ARPCNodeCtlBasicCallResHandler struct {
@@ -975,9 +3158,9 @@ func NewChannelledARPCNodeCtlBasicRespHandler() (
 	rh *ARPCNodeCtlBasicCallResHandler,
 ) {
 	var (
-		ret_timedout chan struct{}
-		ret_closed   chan struct{}
-		ret_call     chan *ARPCCall
+		ret_timedout = make(chan struct{}, 1)
+		ret_closed   = make(chan struct{}, 1)
+		ret_call     = make(chan *ARPCCall, 1)
 	)
 
 	ret := &ARPCNodeCtlBasicCallResHandler{
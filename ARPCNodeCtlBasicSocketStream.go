@@ -0,0 +1,331 @@
+package goarpcsolution
+
+import (
+	"errors"
+	"io"
+	"net"
+	"sync"
+
+	"github.com/AnimusPEXUS/gouuidtools"
+)
+
+// push-mode streaming of connected sockets.
+//
+// reading side calls SocketStreamStart() with window - count of bytes,
+// which it's ready to buffer. owning side reads socket and sends data
+// with SocketStreamData notifications, while it has credit. reading side
+// returns consumed bytes as credit with SocketStreamCredit notifications.
+// writing is done with SocketWrite() as in pull mode.
+//
+// notifications must be delivered in order: chunks are numbered and
+// lost or reordered chunk breaks the stream
+
+// reads connected socket and sends it's data to remote node
+type xARPCNodeCtlBasicSocketPump struct {
+	cs *ARPCNodeCtlBasicConnectedSocketR
+
+	// credit never exceeds window
+	window int
+
+	mtx     sync.Mutex
+	cond    *sync.Cond
+	credit  int
+	stopped bool
+}
+
+func newARPCNodeCtlBasicSocketPump(
+	cs *ARPCNodeCtlBasicConnectedSocketR,
+	window int,
+) *xARPCNodeCtlBasicSocketPump {
+	self := new(xARPCNodeCtlBasicSocketPump)
+	self.cs = cs
+	self.cond = sync.NewCond(&self.mtx)
+	self.window = window
+	self.credit = window
+	return self
+}
+
+func (self *xARPCNodeCtlBasicSocketPump) addCredit(credit int) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.credit += credit
+	if self.credit > self.window {
+		self.credit = self.window
+	}
+	self.cond.Broadcast()
+}
+
+func (self *xARPCNodeCtlBasicSocketPump) stop() {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.stopped = true
+	self.cond.Broadcast()
+}
+
+// waits for credit. 0 if stopped
+func (self *xARPCNodeCtlBasicSocketPump) waitCredit() int {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	for self.credit <= 0 && !self.stopped {
+		self.cond.Wait()
+	}
+
+	if self.stopped {
+		return 0
+	}
+
+	return self.credit
+}
+
+// false if stopped
+func (self *xARPCNodeCtlBasicSocketPump) spendCredit(n int) bool {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.credit -= n
+	return !self.stopped
+}
+
+// socket's lease is held until run() returns (see SocketStreamStart())
+func (self *xARPCNodeCtlBasicSocketPump) run(node *ARPCNode) {
+	defer self.cs.Lease.leave()
+
+	seq := 0
+
+	for {
+		size := self.waitCredit()
+		if size == 0 {
+			return
+		}
+
+		if size > ARPC_REMOTE_CONN_MAX_READ_SIZE {
+			size = ARPC_REMOTE_CONN_MAX_READ_SIZE
+		}
+
+		buf := make([]byte, size)
+		n, err := self.cs.ConnectedSocket.Read(buf)
+
+		if !self.spendCredit(n) || node.IsClosed() {
+			return
+		}
+
+		if n == 0 && err == nil {
+			continue
+		}
+
+		var stream_err error
+		if err != nil {
+			stream_err = socketErrorToRemote(err)
+		}
+
+		send_err := node.SocketStreamData(
+			self.cs.ConnectedSocketId,
+			seq,
+			buf[:n],
+			stream_err,
+		)
+		seq++
+
+		if send_err != nil || err != nil {
+			return
+		}
+	}
+}
+
+func (self *ARPCNodeCtlBasic) SocketStreamStart(
+	connected_socket_id *gouuidtools.UUID,
+	window int,
+) (
+	err_processing_not_internal, err_processing_internal error,
+) {
+	if window <= 0 {
+		return NewARPCError(
+			ARPCErrorCodeInvalidArgument,
+			"window must be > 0",
+			nil,
+		), nil
+	}
+
+	cs := self.getConnectedSocketR(connected_socket_id)
+	if cs == nil {
+		return self.notFoundError(connected_socket_id, "connected socket"), nil
+	}
+
+	if cs.ConnectedSocket == nil {
+		return nil, errors.New("connected socket have no payload")
+	}
+
+	node := self.node
+	if node == nil {
+		return nil, errors.New("node not set")
+	}
+
+	self.connected_sockets_mtx.Lock()
+	if cs.pump != nil {
+		self.connected_sockets_mtx.Unlock()
+		return NewARPCError(
+			ARPCErrorCodeInvalidState,
+			"connected socket already streamed",
+			nil,
+		), nil
+	}
+	pump := newARPCNodeCtlBasicSocketPump(cs, window)
+	cs.pump = pump
+	self.connected_sockets_mtx.Unlock()
+
+	// streamed socket doesn't expire, even if it's idle
+	cs.Lease.enter()
+
+	go pump.run(node)
+
+	return nil, nil
+}
+
+func (self *ARPCNodeCtlBasic) SocketStreamCredit(
+	connected_socket_id *gouuidtools.UUID,
+	credit int,
+) {
+	cs := self.getConnectedSocketR(connected_socket_id)
+	if cs == nil {
+		return
+	}
+
+	self.connected_sockets_mtx.Lock()
+	pump := cs.pump
+	self.connected_sockets_mtx.Unlock()
+
+	if pump != nil && credit > 0 {
+		pump.addCredit(credit)
+	}
+}
+
+func (self *ARPCNodeCtlBasic) SocketStreamData(
+	connected_socket_id *gouuidtools.UUID,
+	seq int,
+	data []byte,
+	stream_err error,
+) {
+	conn := self.getStreamConn(connected_socket_id)
+	if conn == nil {
+		return
+	}
+
+	conn.pushStreamData(seq, data, stream_err)
+}
+
+func (self *ARPCNodeCtlBasic) getStreamConn(
+	connected_socket_id *gouuidtools.UUID,
+) *ARPCRemoteConn {
+	self.stream_conns_mtx.Lock()
+	defer self.stream_conns_mtx.Unlock()
+	return self.stream_conns[connected_socket_id.Format()]
+}
+
+// returned net.Conn receives data, pushed by remote node. window is
+// count of bytes, which can be buffered locally (0 -
+// ARPC_REMOTE_CONN_STREAM_WINDOW). writing works as with SocketGetConn()
+func (self *ARPCNodeCtlBasic) SocketGetStreamConn(
+	connected_socket_id *gouuidtools.UUID,
+	window int,
+) (net.Conn, error) {
+	node := self.node
+	if node == nil {
+		return nil, errors.New("node not set")
+	}
+
+	if connected_socket_id == nil || connected_socket_id.IsNil() {
+		return nil, errors.New("invalid connected_socket_id")
+	}
+
+	if window <= 0 {
+		window = ARPC_REMOTE_CONN_STREAM_WINDOW
+	}
+
+	conn := NewARPCRemoteConnStream(
+		node,
+		connected_socket_id,
+		self.ResponseTimeout,
+		window,
+	)
+
+	key := connected_socket_id.Format()
+
+	self.stream_conns_mtx.Lock()
+	if _, ok := self.stream_conns[key]; ok {
+		self.stream_conns_mtx.Unlock()
+		return nil, errors.New("connected socket already streamed")
+	}
+	self.stream_conns[key] = conn
+	self.stream_conns_mtx.Unlock()
+
+	conn.on_close = func() {
+		self.stream_conns_mtx.Lock()
+		defer self.stream_conns_mtx.Unlock()
+		if self.stream_conns[key] == conn {
+			delete(self.stream_conns, key)
+		}
+	}
+
+	timedout, closed, result_err, err :=
+		node.SocketStreamStart(connected_socket_id, window, self.ResponseTimeout)
+	err = remoteResultToError(timedout, closed, result_err, err)
+	if err != nil {
+		conn.on_close()
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// opens remote listening socket and returns resulting connection in
+// push mode (see SocketGetStreamConn())
+func (self *ARPCNodeCtlBasic) SocketDialStream(
+	listening_socket_id *gouuidtools.UUID,
+	window int,
+) (net.Conn, error) {
+	node := self.node
+	if node == nil {
+		return nil, errors.New("node not set")
+	}
+
+	connected_socket_id, timedout, closed, result_err, err :=
+		node.SocketOpen(listening_socket_id, self.ResponseTimeout)
+	err = remoteResultToError(timedout, closed, result_err, err)
+	if err != nil {
+		return nil, err
+	}
+
+	conn, err := self.SocketGetStreamConn(connected_socket_id, window)
+	if err != nil {
+		node.SocketClose(connected_socket_id, self.ResponseTimeout)
+		return nil, err
+	}
+
+	return conn, nil
+}
+
+// remote socket is deleted: stream ends after buffered data is read
+func (self *ARPCNodeCtlBasic) streamConnRemoved(
+	connected_socket_id *gouuidtools.UUID,
+) {
+	conn := self.getStreamConn(connected_socket_id)
+	if conn == nil {
+		return
+	}
+
+	conn.pushStreamEnd(io.EOF)
+}
+
+// local node is closed: streams end after buffered data is read
+func (self *ARPCNodeCtlBasic) closeStreamConns() {
+	self.stream_conns_mtx.Lock()
+	conns := make([]*ARPCRemoteConn, 0, len(self.stream_conns))
+	for _, i := range self.stream_conns {
+		conns = append(conns, i)
+	}
+	self.stream_conns_mtx.Unlock()
+
+	for _, i := range conns {
+		i.pushStreamEnd(net.ErrClosed)
+	}
+}
@@ -0,0 +1,706 @@
+package goarpcsolution
+
+import (
+	"errors"
+	"io"
+	"net"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AnimusPEXUS/gouuidtools"
+)
+
+var testClockStart = time.Date(2020, 1, 1, 0, 0, 0, 0, time.UTC)
+
+// controller without node, driven by manual clock
+func newTestCtl(t *testing.T) (*ARPCNodeCtlBasic, *ARPCClockManual) {
+	t.Helper()
+
+	clock := NewARPCClockManual(testClockStart)
+
+	ctl := NewARPCNodeCtlBasicWithClock(clock)
+	ctl.SetDebug(false)
+	t.Cleanup(ctl.Close)
+
+	return ctl, clock
+}
+
+func newTestUUID(t *testing.T) *gouuidtools.UUID {
+	t.Helper()
+
+	r, err := gouuidtools.NewUUIDRegistry()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	id, err := r.GenUUID()
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return id
+}
+
+func TestARPCNodeCtlBasicExpiredError(t *testing.T) {
+	ctl, clock := newTestCtl(t)
+
+	b, err := ctl.addBufferR(nil, NewARPCBufferMemObject("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(ctl.DefaultBufferTTL)
+
+	_, err_not_internal, err_internal := ctl.BufferGetInfo(b.BufferId)
+	if err_internal != nil {
+		t.Fatal(err_internal)
+	}
+	if !errors.Is(err_not_internal, ARPCErrExpired) {
+		t.Errorf("expected expired error, got %v", err_not_internal)
+	}
+
+	_, err_not_internal, _ = ctl.BufferGetInfo(newTestUUID(t))
+	if !errors.Is(err_not_internal, ARPCErrNotFound) {
+		t.Errorf("expected not found error, got %v", err_not_internal)
+	}
+}
+
+// record lookups, which don't renew leases (unlike get*R())
+
+func testHasBuffer(ctl *ARPCNodeCtlBasic, buffer_id *gouuidtools.UUID) bool {
+	ctl.buffers_mtx.Lock()
+	defer ctl.buffers_mtx.Unlock()
+
+	for _, i := range ctl.buffers {
+		if uuidsEqual(i.BufferId, buffer_id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func testHasCall(ctl *ARPCNodeCtlBasic, call_id *gouuidtools.UUID) bool {
+	ctl.calls_mtx.Lock()
+	defer ctl.calls_mtx.Unlock()
+
+	for _, i := range ctl.calls {
+		if uuidsEqual(i.CallId, call_id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func testHasConnectedSocket(
+	ctl *ARPCNodeCtlBasic,
+	connected_socket_id *gouuidtools.UUID,
+) bool {
+	ctl.connected_sockets_mtx.Lock()
+	defer ctl.connected_sockets_mtx.Unlock()
+
+	for _, i := range ctl.connected_sockets {
+		if uuidsEqual(i.ConnectedSocketId, connected_socket_id) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// waits until operation, holding lease, is started
+func waitTestLeaseBusy(t *testing.T, lease *ARPCNodeCtlBasicLease) {
+	t.Helper()
+
+	deadline := time.Now().Add(5 * time.Second)
+	for {
+		lease.mtx.Lock()
+		busy := lease.busy
+		lease.mtx.Unlock()
+
+		if busy != 0 {
+			return
+		}
+
+		if time.Now().After(deadline) {
+			t.Fatal("lease isn't held")
+		}
+
+		time.Sleep(time.Millisecond)
+	}
+}
+
+// registers listening socket, which opens net.Pipe() connections. remote
+// ends of connections are sent to returned channel
+func listenTestPipe(
+	t *testing.T,
+	ctl *ARPCNodeCtlBasic,
+) (*gouuidtools.UUID, *ARPCListeningSocketDialer, chan net.Conn) {
+	t.Helper()
+
+	remotes := make(chan net.Conn, 10)
+
+	ls := NewARPCListeningSocketDialer(
+		func() (net.Conn, error) {
+			local, remote := net.Pipe()
+			t.Cleanup(func() { remote.Close() })
+			remotes <- remote
+			return local, nil
+		},
+	)
+
+	ls_id, err := ctl.SocketListen(nil, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ls_id, ls, remotes
+}
+
+func TestARPCNodeCtlBasicLeaseExpiry(t *testing.T) {
+	ctl, clock := newTestCtl(t)
+	ctl.DefaultBufferTTL = time.Minute
+
+	b, err := ctl.addBufferR(nil, NewARPCBufferMemObject("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(time.Minute - time.Second)
+	if !testHasBuffer(ctl, b.BufferId) {
+		t.Fatal("buffer expired before TTL")
+	}
+
+	clock.Advance(time.Second)
+	if testHasBuffer(ctl, b.BufferId) {
+		t.Fatal("buffer not expired after TTL")
+	}
+
+	if clock.PendingTimers() != 0 {
+		t.Errorf("%d timers left", clock.PendingTimers())
+	}
+}
+
+func TestARPCNodeCtlBasicLeaseTouch(t *testing.T) {
+	ctl, clock := newTestCtl(t)
+	ctl.DefaultBufferTTL = time.Minute
+
+	b, err := ctl.addBufferR(nil, NewARPCBufferMemObject("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(40 * time.Second)
+
+	ttl, err_not_internal, err_internal := ctl.Touch(ARPCObjectTypeBuffer, b.BufferId)
+	if err_not_internal != nil || err_internal != nil {
+		t.Fatal(err_not_internal, err_internal)
+	}
+	if ttl != time.Minute {
+		t.Errorf("Touch() returned %v", ttl)
+	}
+
+	clock.Advance(40 * time.Second)
+	if !testHasBuffer(ctl, b.BufferId) {
+		t.Fatal("touched buffer expired")
+	}
+
+	// access renews lease too
+	_, err_not_internal, _ = ctl.BufferGetInfo(b.BufferId)
+	if err_not_internal != nil {
+		t.Fatal(err_not_internal)
+	}
+
+	clock.Advance(59 * time.Second)
+	if !testHasBuffer(ctl, b.BufferId) {
+		t.Fatal("accessed buffer expired")
+	}
+
+	clock.Advance(time.Second)
+	if testHasBuffer(ctl, b.BufferId) {
+		t.Fatal("buffer not expired after TTL")
+	}
+}
+
+func TestARPCNodeCtlBasicLeasePinning(t *testing.T) {
+	ctl, clock := newTestCtl(t)
+	ctl.DefaultBufferTTL = time.Minute
+
+	b, err := ctl.addBufferR(nil, NewARPCBufferMemObject("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = ctl.SetObjectPinned(ARPCObjectTypeBuffer, b.BufferId, true)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ttl, _, _ := ctl.Touch(ARPCObjectTypeBuffer, b.BufferId)
+	if ttl != 0 {
+		t.Errorf("Touch() of pinned object returned %v", ttl)
+	}
+
+	clock.Advance(time.Hour)
+	if !testHasBuffer(ctl, b.BufferId) {
+		t.Fatal("pinned buffer expired")
+	}
+
+	// unpinning renews lease
+	err = ctl.SetObjectPinned(ARPCObjectTypeBuffer, b.BufferId, false)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	clock.Advance(time.Minute - time.Second)
+	if !testHasBuffer(ctl, b.BufferId) {
+		t.Fatal("unpinned buffer expired before TTL")
+	}
+
+	clock.Advance(time.Second)
+	if testHasBuffer(ctl, b.BufferId) {
+		t.Fatal("unpinned buffer not expired after TTL")
+	}
+}
+
+// expired call releases it's args and closes awaiting handler
+func TestARPCNodeCtlBasicCallDeleted(t *testing.T) {
+	ctl, clock := newTestCtl(t)
+	ctl.DefaultCallTTL = time.Minute
+	ctl.DefaultBufferTTL = time.Hour
+
+	call_id := newTestUUID(t)
+	arg := NewARPCCallArgFromValue(NewARPCBufferMemObject("", ""))
+
+	_, closed, _, rh := NewChannelledARPCNodeCtlBasicRespHandler()
+
+	err := ctl.saveCall(
+		call_id,
+		nil,
+		"name",
+		[]*ARPCCallArg{arg},
+		0,
+		"",
+		nil,
+		false,
+		rh,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctl.handlers = append(
+		ctl.handlers,
+		&xARPCNodeCtlBasicCallResHandlerWrapper{
+			handler: rh,
+			id:      call_id,
+			expiry:  ctl.expiry.newEntry(func() {}),
+		},
+	)
+
+	if !testHasBuffer(ctl, arg.Buffer.Id) {
+		t.Fatal("arg buffer isn't registered")
+	}
+
+	clock.Advance(time.Minute)
+
+	if testHasCall(ctl, call_id) {
+		t.Fatal("call not expired")
+	}
+
+	if testHasBuffer(ctl, arg.Buffer.Id) {
+		t.Error("buffer owned by expired call isn't released")
+	}
+
+	select {
+	case <-closed:
+	case <-time.After(5 * time.Second):
+		t.Error("handler isn't closed")
+	}
+}
+
+func TestARPCNodeCtlBasicHandlerTimeout(t *testing.T) {
+	ctl, clock := newTestCtl(t)
+	ctl.DefaultCallTTL = time.Minute
+
+	node := NewARPCNode(ctl)
+	node.PushMessageToOutsideCB = func(data []byte) error { return nil }
+
+	timedout, closed, _, rh := NewChannelledARPCNodeCtlBasicRespHandler()
+
+	call_id, err := ctl.Call("name", nil, false, rh)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// call outlives handler
+	clock.Advance(30 * time.Second)
+	ctl.Touch(ARPCObjectTypeCall, call_id)
+	clock.Advance(30 * time.Second)
+
+	select {
+	case <-timedout:
+	case <-closed:
+		t.Fatal("handler closed instead of timeout")
+	case <-time.After(5 * time.Second):
+		t.Fatal("handler not timed out")
+	}
+
+	if !testHasCall(ctl, call_id) {
+		t.Error("touched call expired")
+	}
+
+	if ctl.popHandler(call_id) != nil {
+		t.Error("timed out handler isn't removed")
+	}
+}
+
+// lease, renewed after scheduler took it as due, must not expire
+func TestARPCNodeCtlBasicLeaseRenewedWhileDue(t *testing.T) {
+	ctl, clock := newTestCtl(t)
+
+	b0, err := ctl.addBufferR(nil, NewARPCBufferMemObject("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b1, err := ctl.addBufferR(nil, NewARPCBufferMemObject("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	b0.Lease.SetTTL(time.Minute)
+	b1.Lease.SetTTL(time.Minute + time.Second)
+
+	// both are due on same fire(). b0 goes first and renews b1
+	b0.Lease.stop()
+	b0.Lease.stopped = false
+	b0.Lease.entry = nil
+	b0.Lease.start(
+		func() {
+			b1.Lease.Touch()
+			ctl.deleteBufferR(b0, ARPCObjectRemoveReasonExpired)
+		},
+	)
+
+	clock.Advance(2 * time.Minute)
+
+	if testHasBuffer(ctl, b0.BufferId) {
+		t.Error("b0 not expired")
+	}
+
+	if !testHasBuffer(ctl, b1.BufferId) {
+		t.Error("b1 expired after renewal")
+	}
+}
+
+func TestARPCNodeCtlBasicSocketReadHoldsLease(t *testing.T) {
+	ctl, clock := newTestCtl(t)
+	ctl.DefaultConnectedSocketTTL = time.Minute
+
+	ls_id, _, remotes := listenTestPipe(t, ctl)
+
+	cs_id, err_not_internal, err_internal := ctl.SocketOpen(ls_id)
+	if err_not_internal != nil || err_internal != nil {
+		t.Fatal(err_not_internal, err_internal)
+	}
+	remote := <-remotes
+
+	type result struct {
+		b   []byte
+		err error
+	}
+
+	res := make(chan result, 1)
+	go func() {
+		b, err_not_internal, _ := ctl.SocketRead(cs_id, 10)
+		res <- result{b, err_not_internal}
+	}()
+
+	ctl.connected_sockets_mtx.Lock()
+	cs := ctl.connected_sockets[0]
+	ctl.connected_sockets_mtx.Unlock()
+
+	waitTestLeaseBusy(t, cs.Lease)
+
+	clock.Advance(time.Hour)
+	if !testHasConnectedSocket(ctl, cs_id) {
+		t.Fatal("socket expired while read is blocked")
+	}
+
+	_, err := remote.Write([]byte("x"))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r := <-res
+	if r.err != nil || string(r.b) != "x" {
+		t.Fatalf("SocketRead() returned %q, %v", r.b, r.err)
+	}
+
+	// finished read renews lease
+	clock.Advance(time.Minute - time.Second)
+	if !testHasConnectedSocket(ctl, cs_id) {
+		t.Fatal("socket expired before TTL")
+	}
+
+	clock.Advance(time.Second)
+	if testHasConnectedSocket(ctl, cs_id) {
+		t.Fatal("idle socket not expired")
+	}
+
+	// connection, opened by controller, is closed with record
+	remote.SetDeadline(time.Now().Add(5 * time.Second))
+	_, err = remote.Read(make([]byte, 1))
+	if err != io.EOF {
+		t.Errorf("expected EOF on remote end, got %v", err)
+	}
+}
+
+func TestARPCNodeCtlBasicSocketStreamHoldsLease(t *testing.T) {
+	ctl, clock := newTestCtl(t)
+	ctl.DefaultConnectedSocketTTL = time.Minute
+
+	node := NewARPCNode(ctl)
+	node.PushMessageToOutsideCB = func(data []byte) error { return nil }
+
+	ls_id, _, remotes := listenTestPipe(t, ctl)
+
+	cs_id, err_not_internal, err_internal := ctl.SocketOpen(ls_id)
+	if err_not_internal != nil || err_internal != nil {
+		t.Fatal(err_not_internal, err_internal)
+	}
+	<-remotes
+
+	err_not_internal, err_internal = ctl.SocketStreamStart(cs_id, 1024)
+	if err_not_internal != nil || err_internal != nil {
+		t.Fatal(err_not_internal, err_internal)
+	}
+
+	clock.Advance(time.Hour)
+	if !testHasConnectedSocket(ctl, cs_id) {
+		t.Fatal("streamed socket expired")
+	}
+}
+
+// application keeps using it's listener after record expired
+func TestARPCNodeCtlBasicAppSocketNotClosedOnExpiry(t *testing.T) {
+	ctl, clock := newTestCtl(t)
+	ctl.DefaultListeningSocketTTL = time.Minute
+
+	ls_id, ls, _ := listenTestPipe(t, ctl)
+
+	clock.Advance(time.Minute)
+
+	_, err_not_internal, _ := ctl.SocketOpen(ls_id)
+	if !errors.Is(err_not_internal, ARPCErrExpired) {
+		t.Fatalf("expected expired error, got %v", err_not_internal)
+	}
+
+	conn, err := ls.Open()
+	if err != nil {
+		t.Fatalf("listening socket is closed on expiry: %v", err)
+	}
+	conn.Close()
+
+	// but controller closes everything on Close()
+	_, err = ctl.SocketListen(nil, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctl.Close()
+
+	_, err = ls.Open()
+	if err == nil {
+		t.Error("listening socket isn't closed by controller Close()")
+	}
+}
+
+func TestARPCNodeCtlBasicSocketPumpCredit(t *testing.T) {
+	pump := newARPCNodeCtlBasicSocketPump(nil, 10)
+
+	pump.spendCredit(4)
+	pump.addCredit(100)
+
+	if credit := pump.waitCredit(); credit != 10 {
+		t.Errorf("credit %d exceeds window", credit)
+	}
+}
+
+// endless zeroes
+type testZeroReaderAt struct{}
+
+func (testZeroReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	for i := range p {
+		p[i] = 0
+	}
+	return len(p), nil
+}
+
+// counts reads of testZeroReaderAt
+type testCountingReaderAt struct {
+	testZeroReaderAt
+	reads int32
+}
+
+func (self *testCountingReaderAt) ReadAt(p []byte, off int64) (int, error) {
+	atomic.AddInt32(&self.reads, 1)
+	return self.testZeroReaderAt.ReadAt(p, off)
+}
+
+// ids and times of file items are resolved without reading data
+func TestARPCNodeCtlBasicFileItemsMeta(t *testing.T) {
+	ctl, _ := newTestCtl(t)
+
+	r := new(testCountingReaderAt)
+
+	b, err := ctl.addBufferR(nil, NewARPCBufferReaderAt(r, 1<<30, "", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	first := &ARPCBufferItemSpecifier{}
+	first.SetTime(time.Now().Add(-time.Hour))
+	last := &ARPCBufferItemSpecifier{}
+	last.SetIndex(-1)
+
+	ids, err_not_internal, err_internal := ctl.BufferGetItemsIds(
+		b.BufferId,
+		first,
+		last,
+	)
+	if err_not_internal != nil || err_internal != nil {
+		t.Fatal(err_not_internal, err_internal)
+	}
+	if len(ids) != ARPC_BUFFER_ITEMS_IDS_MAX_COUNT || ids[0] != "0" {
+		t.Fatalf("%d ids, starting with %v", len(ids), ids[:1])
+	}
+
+	_, err_not_internal, err_internal = ctl.BufferGetItemsTimesByIds(
+		b.BufferId,
+		ids,
+	)
+	if err_not_internal != nil || err_internal != nil {
+		t.Fatal(err_not_internal, err_internal)
+	}
+
+	_, err_not_internal, err_internal = ctl.BufferGetItemsLastTime(b.BufferId)
+	if err_not_internal != nil || err_internal != nil {
+		t.Fatal(err_not_internal, err_internal)
+	}
+
+	if n := atomic.LoadInt32(&r.reads); n != 0 {
+		t.Errorf("data is read %d times", n)
+	}
+}
+
+func TestARPCNodeCtlBasicItemsByIdsLimit(t *testing.T) {
+	ctl, _ := newTestCtl(t)
+
+	b, err := ctl.addBufferR(
+		nil,
+		NewARPCBufferReaderAt(testZeroReaderAt{}, 1<<30, "", ""),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]string, ARPC_BUFFER_ITEMS_IDS_MAX_COUNT+1)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+
+	_, err_not_internal, _ := ctl.BufferGetItemsByIds(b.BufferId, ids)
+	if !errors.Is(err_not_internal, ARPCErrInvalidArgument) {
+		t.Errorf("expected invalid argument error, got %v", err_not_internal)
+	}
+
+	_, err_not_internal, _ = ctl.BufferGetItemsTimesByIds(b.BufferId, ids)
+	if !errors.Is(err_not_internal, ARPCErrInvalidArgument) {
+		t.Errorf("expected invalid argument error, got %v", err_not_internal)
+	}
+
+	// result is limited by size of data
+	items, err_not_internal, err_internal := ctl.BufferGetItemsByIds(
+		b.BufferId,
+		ids[:100],
+	)
+	if err_not_internal != nil || err_internal != nil {
+		t.Fatal(err_not_internal, err_internal)
+	}
+	expected := ARPC_BUFFER_BINARY_MAX_SLICE_SIZE / ARPC_BUFFER_FILE_ITEM_SIZE
+	if len(items) != expected {
+		t.Errorf("%d items returned", len(items))
+	}
+}
+
+func TestARPCNodeCtlBasicBinarySliceLimit(t *testing.T) {
+	ctl, _ := newTestCtl(t)
+
+	b, err := ctl.addBufferR(
+		nil,
+		NewARPCBufferReaderAt(testZeroReaderAt{}, 1<<30, "", ""),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	data, err_not_internal, err_internal := ctl.BufferBinaryGetSlice(
+		b.BufferId,
+		10,
+		10+ARPC_BUFFER_BINARY_MAX_SLICE_SIZE,
+	)
+	if err_not_internal != nil || err_internal != nil {
+		t.Fatal(err_not_internal, err_internal)
+	}
+	if len(data) != ARPC_BUFFER_BINARY_MAX_SLICE_SIZE {
+		t.Errorf("slice of %d bytes", len(data))
+	}
+
+	_, err_not_internal, err_internal = ctl.BufferBinaryGetSlice(
+		b.BufferId,
+		10,
+		11+ARPC_BUFFER_BINARY_MAX_SLICE_SIZE,
+	)
+	if err_internal != nil {
+		t.Fatal(err_internal)
+	}
+	if !errors.Is(err_not_internal, ARPCErrInvalidArgument) {
+		t.Errorf("expected invalid argument error, got %v", err_not_internal)
+	}
+
+	_, err_not_internal, _ = ctl.BufferBinaryGetSlice(b.BufferId, 0, 1<<30)
+	if !errors.Is(err_not_internal, ARPCErrInvalidArgument) {
+		t.Errorf("expected invalid argument error, got %v", err_not_internal)
+	}
+}
+
+func TestARPCNodeCtlBasicInvalidItemSpecifier(t *testing.T) {
+	ctl, _ := newTestCtl(t)
+
+	b, err := ctl.addBufferR(nil, NewARPCBufferMemObject("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	last := &ARPCBufferItemSpecifier{Value: "#:-1"}
+
+	for _, value := range []string{"x", "#:x", "T:x"} {
+		_, err_not_internal, err_internal := ctl.BufferGetItemsIds(
+			b.BufferId,
+			&ARPCBufferItemSpecifier{Value: value},
+			last,
+		)
+		if err_internal != nil {
+			t.Fatal(err_internal)
+		}
+		if !errors.Is(err_not_internal, ARPCErrInvalidArgument) {
+			t.Errorf("%q: expected invalid argument error, got %v",
+				value, err_not_internal)
+		}
+	}
+}
@@ -32,16 +32,20 @@ type ARPCNodeCtlI interface {
 	// Notifications
 	// ----------------------------------------
 
-	// inform node about new call availability
+	// inform node about new call availability.
+	// this is called in separate goroutine, so resulting errors are passed
+	// to node's OnAsyncHandlerErrorsCB
+	//   error #0 - if protocol error
+	//   error #1 - other errors
 	NewCall(
 		call_id *gouuidtools.UUID,
 		response_on *gouuidtools.UUID,
-	)
+	) (error, error)
 
 	// inform node about new buffer availability
-	// NewBuffer(
-	// 	buffer_id *gouuidtools.UUID,
-	// )
+	NewBuffer(
+		buffer_id *gouuidtools.UUID,
+	)
 
 	// you have to subscribe to bufffer updates to receive this
 	// notifications (see BufferSubscribeOnUpdatesNotification())
@@ -55,9 +59,24 @@ type ARPCNodeCtlI interface {
 	)
 
 	// inform node about new socket availability
-	// NewSocket(
-	// 	listening_socket_id *gouuidtools.UUID,
-	// )
+	NewSocket(
+		listening_socket_id *gouuidtools.UUID,
+	)
+
+	// remote buffer or transmission is finished and won't change anymore
+	ObjectFinished(
+		object_type ARPCObjectType,
+		object_id *gouuidtools.UUID,
+	)
+
+	// remote node deleted object (by CallClose(), SocketClose(), expiry
+	// or as argument of deleted call). object_type is one of
+	// ARPCObjectType* values
+	ObjectRemoved(
+		object_type ARPCObjectType,
+		object_id *gouuidtools.UUID,
+		reason ARPCObjectRemoveReason,
+	)
 
 	// ----------------------------------------
 	// Basic Calls
@@ -69,6 +88,15 @@ type ARPCNodeCtlI interface {
 		err_processing_not_internal, err_processing_internal error,
 	)
 
+	// returns general call information: it's id, name (if it's call)
+	// or id of call this call is reply to (if it's reply)
+	CallGetInfo(
+		call_id *gouuidtools.UUID,
+	) (
+		info *ARPCCallForJSON,
+		err_processing_not_internal, err_processing_internal error,
+	)
+
 	// returns name of method which call is calling
 	CallGetName(
 		call_id *gouuidtools.UUID,
@@ -85,7 +113,8 @@ type ARPCNodeCtlI interface {
 		err_processing_not_internal, err_processing_internal error,
 	)
 
-	// returns ARPCArgInfo for selected arg of selected call
+	// returns ARPCArgInfo for selected args of selected call.
+	// first and last are indexes of first and last (inclusive) args
 	CallGetArgValues(
 		call_id *gouuidtools.UUID,
 		first, last int,
@@ -101,6 +130,20 @@ type ARPCNodeCtlI interface {
 		err_processing_not_internal, err_processing_internal error,
 	)
 
+	// ----------------------------------------
+	// Objects
+	// ----------------------------------------
+
+	// renews lease of object, so it isn't expired.
+	// returns time left until expiry (0 if object never expires)
+	Touch(
+		object_type ARPCObjectType,
+		object_id *gouuidtools.UUID,
+	) (
+		ttl time.Duration,
+		err_processing_not_internal, err_processing_internal error,
+	)
+
 	// ----------------------------------------
 	// Buffers
 	// ----------------------------------------
@@ -109,7 +152,7 @@ type ARPCNodeCtlI interface {
 	BufferGetInfo(
 		buffer_id *gouuidtools.UUID,
 	) (
-		info *ARPCCallForJSON,
+		info *ARPCBufferInfo,
 		err_processing_not_internal, err_processing_internal error,
 	)
 
@@ -123,7 +166,10 @@ type ARPCNodeCtlI interface {
 		err_processing_not_internal, err_processing_internal error,
 	)
 
-	// get exact ids of buffer items using Buffer Item Specifiers
+	// get exact ids of buffer items using Buffer Item Specifiers.
+	// result may be limited (ARPCNodeCtlBasic returns up to
+	// ARPC_BUFFER_ITEMS_IDS_MAX_COUNT ids): rest of range is requested
+	// starting with last returned id
 	BufferGetItemsIds(
 		buffer_id *gouuidtools.UUID,
 		first_spec, last_spec *ARPCBufferItemSpecifier,
@@ -143,7 +189,9 @@ type ARPCNodeCtlI interface {
 		err_processing_not_internal, err_processing_internal error,
 	)
 
-	// retrive actual buffer items with payloads
+	// retrive actual buffer items with payloads.
+	// result may be shorter than ids (ARPCNodeCtlBasic limits size of
+	// binary values): rest of items is requested by caller
 	BufferGetItemsByIds(
 		buffer_id *gouuidtools.UUID,
 		ids []string,
@@ -188,6 +236,8 @@ type ARPCNodeCtlI interface {
 		err_processing_not_internal, err_processing_internal error,
 	)
 
+	// like the CallGetList(): result is buffer with ids of buffers,
+	// updates of which are subscribed on
 	BufferGetListSubscribedUpdatesNotifications() (
 		buffer_id *gouuidtools.UUID,
 		err_processing_not_internal, err_processing_internal error,
@@ -197,6 +247,7 @@ type ARPCNodeCtlI interface {
 
 	// BufferBinary functions works only if buffer in binary mode
 
+	// size in bytes
 	BufferBinaryGetSize(
 		buffer_id *gouuidtools.UUID,
 	) (
@@ -204,6 +255,7 @@ type ARPCNodeCtlI interface {
 		err_processing_not_internal, err_processing_internal error,
 	)
 
+	// bytes from start_index to end_index (not including)
 	BufferBinaryGetSlice(
 		buffer_id *gouuidtools.UUID,
 		start_index, end_index int,
@@ -297,4 +349,33 @@ type ARPCNodeCtlI interface {
 	) (
 		err_processing_not_internal, err_processing_internal error,
 	)
+
+	// ---v--- push-mode streaming ---v---
+
+	// start sending socket's data to remote node by SocketStreamData
+	// notifications. window is count of bytes, which can be sent before
+	// remote node gives more credit with SocketStreamCredit
+	SocketStreamStart(
+		connected_socket_id *gouuidtools.UUID,
+		window int,
+	) (
+		err_processing_not_internal, err_processing_internal error,
+	)
+
+	// notification: remote node consumed credit bytes of streamed socket
+	SocketStreamCredit(
+		connected_socket_id *gouuidtools.UUID,
+		credit int,
+	)
+
+	// notification: chunk of remote socket's data. stream_err is set
+	// (as *ARPCError) in last chunk if remote socket reading failed
+	SocketStreamData(
+		connected_socket_id *gouuidtools.UUID,
+		seq int,
+		data []byte,
+		stream_err error,
+	)
+
+	// ---^--- push-mode streaming ---^---
 }
@@ -0,0 +1,254 @@
+package goarpcsolution
+
+import (
+	"errors"
+	"math/rand"
+	"sync"
+	"time"
+)
+
+// knobs for in-memory link between nodes of ARPCNodePair.
+// zero value - instant, lossless and ordered delivery
+type ARPCNodePairOptions struct {
+	// delay before message delivery
+	Latency time.Duration
+
+	// random [0, LatencyJitter) addition to Latency
+	LatencyJitter time.Duration
+
+	// probability [0, 1] of silent message loss
+	DropProbability float64
+
+	// probability [0, 1] of message being delivered before
+	// previous (still undelivered) message
+	ReorderProbability float64
+
+	// seed for drop/reorder/jitter randomness. 0 - seed by current time
+	Seed int64
+
+	// codec of both nodes. nil - ARPCCodecJSON
+	Codec ARPCCodecI
+
+	// results of node.PushMessageFromOutside() on receiving side,
+	// if any of them is not nil. node_index is 0 or 1
+	OnMessageErrorsCB func(node_index int, err_proto error, err error)
+}
+
+// two ARPCNodes with ARPCNodeCtlBasic controllers, connected to each other
+// through in-memory queues. messages are delivered to each node in
+// it's own goroutine, so sending never reenters the sending node.
+type ARPCNodePair struct {
+	Node0 *ARPCNode
+	Ctl0  *ARPCNodeCtlBasic
+
+	Node1 *ARPCNode
+	Ctl1  *ARPCNodeCtlBasic
+
+	options ARPCNodePairOptions
+
+	rand_mtx sync.Mutex
+	rand     *rand.Rand
+
+	to0 *xARPCNodePairQueue
+	to1 *xARPCNodePairQueue
+
+	close_once sync.Once
+}
+
+// options may be nil
+func NewARPCNodePair(options *ARPCNodePairOptions) *ARPCNodePair {
+	self := new(ARPCNodePair)
+
+	if options != nil {
+		self.options = *options
+	}
+
+	seed := self.options.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	self.rand = rand.New(rand.NewSource(seed))
+
+	self.Ctl0 = NewARPCNodeCtlBasic()
+	self.Node0 = NewARPCNode(self.Ctl0)
+
+	self.Ctl1 = NewARPCNodeCtlBasic()
+	self.Node1 = NewARPCNode(self.Ctl1)
+
+	self.Node0.SetCodec(self.options.Codec)
+	self.Node1.SetCodec(self.options.Codec)
+
+	self.to0 = newARPCNodePairQueue(self, 0, self.Node0)
+	self.to1 = newARPCNodePairQueue(self, 1, self.Node1)
+
+	self.Node0.PushMessageToOutsideCB = self.to1.push
+	self.Node1.PushMessageToOutsideCB = self.to0.push
+
+	go self.to0.deliveryLoop()
+	go self.to1.deliveryLoop()
+
+	return self
+}
+
+// stops delivery and closes both nodes
+func (self *ARPCNodePair) Close() {
+	self.close_once.Do(
+		func() {
+			self.to0.close()
+			self.to1.close()
+			self.Node0.Close()
+			self.Node1.Close()
+		},
+	)
+}
+
+func (self *ARPCNodePair) randFloat64() float64 {
+	self.rand_mtx.Lock()
+	defer self.rand_mtx.Unlock()
+	return self.rand.Float64()
+}
+
+func (self *ARPCNodePair) randDuration(max time.Duration) time.Duration {
+	if max <= 0 {
+		return 0
+	}
+	self.rand_mtx.Lock()
+	defer self.rand_mtx.Unlock()
+	return time.Duration(self.rand.Int63n(int64(max)))
+}
+
+type xARPCNodePairMessage struct {
+	data       []byte
+	deliver_at time.Time
+}
+
+type xARPCNodePairQueue struct {
+	pair       *ARPCNodePair
+	node_index int
+	node       *ARPCNode
+
+	mtx     sync.Mutex
+	cond    *sync.Cond
+	pending []*xARPCNodePairMessage
+	closed  bool
+}
+
+func newARPCNodePairQueue(
+	pair *ARPCNodePair,
+	node_index int,
+	node *ARPCNode,
+) *xARPCNodePairQueue {
+	self := new(xARPCNodePairQueue)
+	self.pair = pair
+	self.node_index = node_index
+	self.node = node
+	self.cond = sync.NewCond(&self.mtx)
+	return self
+}
+
+func (self *xARPCNodePairQueue) push(data []byte) error {
+	opts := &self.pair.options
+
+	if opts.DropProbability > 0 &&
+		self.pair.randFloat64() < opts.DropProbability {
+		return nil
+	}
+
+	msg := &xARPCNodePairMessage{
+		// sender may reuse data
+		data: append([]byte(nil), data...),
+		deliver_at: time.Now().
+			Add(opts.Latency).
+			Add(self.pair.randDuration(opts.LatencyJitter)),
+	}
+
+	reorder := opts.ReorderProbability > 0 &&
+		self.pair.randFloat64() < opts.ReorderProbability
+
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.closed {
+		return errors.New("node pair closed")
+	}
+
+	if reorder && len(self.pending) != 0 {
+		last := self.pending[len(self.pending)-1]
+		if last.deliver_at.Before(msg.deliver_at) {
+			msg.deliver_at = last.deliver_at
+		}
+		self.pending = append(self.pending[:len(self.pending)-1], msg, last)
+	} else {
+		self.pending = append(self.pending, msg)
+	}
+
+	self.cond.Broadcast()
+
+	return nil
+}
+
+func (self *xARPCNodePairQueue) close() {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	self.closed = true
+	self.pending = nil
+	self.cond.Broadcast()
+}
+
+// nil if queue closed
+func (self *xARPCNodePairQueue) pop() *xARPCNodePairMessage {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	for {
+		if self.closed {
+			return nil
+		}
+
+		if len(self.pending) == 0 {
+			self.cond.Wait()
+			continue
+		}
+
+		msg := self.pending[0]
+
+		wait := time.Until(msg.deliver_at)
+		if wait > 0 {
+			// wake up on time, or earlier on push() or close()
+			t := time.AfterFunc(
+				wait,
+				func() {
+					self.mtx.Lock()
+					self.cond.Broadcast()
+					self.mtx.Unlock()
+				},
+			)
+			self.cond.Wait()
+			t.Stop()
+			continue
+		}
+
+		self.pending = self.pending[1:]
+		return msg
+	}
+}
+
+func (self *xARPCNodePairQueue) deliveryLoop() {
+	for {
+		msg := self.pop()
+		if msg == nil {
+			return
+		}
+
+		// messages to closed node are dropped
+		err_proto, err := self.node.PushMessageFromOutside(msg.data)
+		if errors.Is(err, ErrARPCClosed) {
+			continue
+		}
+
+		if (err_proto != nil || err != nil) &&
+			self.pair.options.OnMessageErrorsCB != nil {
+			self.pair.options.OnMessageErrorsCB(self.node_index, err_proto, err)
+		}
+	}
+}
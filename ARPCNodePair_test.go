@@ -0,0 +1,660 @@
+package goarpcsolution
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"io"
+	"net"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AnimusPEXUS/gouuidtools"
+)
+
+// pair, which is closed on test end
+func newTestNodePair(
+	t *testing.T,
+	options *ARPCNodePairOptions,
+) *ARPCNodePair {
+	t.Helper()
+
+	pair := NewARPCNodePair(options)
+	pair.Ctl0.SetDebug(false)
+	pair.Ctl1.SetDebug(false)
+	pair.Ctl0.ResponseTimeout = 5 * time.Second
+	pair.Ctl1.ResponseTimeout = 5 * time.Second
+	pair.Node0.CtxResponseTimeout = 5 * time.Second
+	pair.Node1.CtxResponseTimeout = 5 * time.Second
+
+	t.Cleanup(pair.Close)
+
+	return pair
+}
+
+func newTestContext(t *testing.T) context.Context {
+	t.Helper()
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	t.Cleanup(cancel)
+	return ctx
+}
+
+// Ctl1 answers "echo" calls with their args and "fail" calls with
+// ARPCErrorCodeInvalidArgument
+func serveTestCalls(t *testing.T, ctl *ARPCNodeCtlBasic) {
+	ctl.OnCallCB = func(call *ARPCCall) (error, error) {
+		go func() {
+			var err error
+			switch call.Name {
+			case "echo":
+				err = ctl.Reply(call.CallId, call.Args...)
+			case "fail":
+				err = ctl.ReplyWithError(
+					call.CallId,
+					NewARPCError(ARPCErrorCodeInvalidArgument, "fail called", nil),
+				)
+			default:
+				err = ctl.ReplyWithError(call.CallId, ARPCErrUnsupported)
+			}
+			if err != nil {
+				t.Error("reply:", err)
+			}
+		}()
+		return nil, nil
+	}
+}
+
+func TestARPCNodePairCallReply(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	serveTestCalls(t, pair.Ctl1)
+	ctx := newTestContext(t)
+
+	reply, err := pair.Ctl0.CallAndWait(
+		ctx,
+		"echo",
+		NewARPCCallArgFromValue("a"),
+		NewARPCCallNamedArgFromValue("b", true),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if reply.ReplyToId == nil || len(reply.Args) != 2 {
+		t.Fatalf("unexpected reply: %+v", reply)
+	}
+
+	if reply.Args[0].Basic == nil || reply.Args[0].Basic.Value != "a" {
+		t.Errorf("positional arg: %+v", reply.Args[0])
+	}
+
+	if reply.Args[1].Name != "b" ||
+		reply.Args[1].Basic == nil || reply.Args[1].Basic.Value != true {
+		t.Errorf("named arg: %+v", reply.Args[1])
+	}
+
+	res, err := CallTyped[[]string](ctx, pair.Ctl0, "echo", []any{"x", "y"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(res) != 2 || res[0] != "x" || res[1] != "y" {
+		t.Errorf("CallTyped result: %v", res)
+	}
+
+	_, err = pair.Ctl0.CallAndWait(ctx, "fail")
+	if !errors.Is(err, ErrARPCRemote) || !errors.Is(err, ARPCErrInvalidArgument) {
+		t.Errorf("fail call error: %v", err)
+	}
+
+	// reply to call, made with response handler
+	timedout, closed, reply_c, rh := NewChannelledARPCNodeCtlBasicRespHandler()
+
+	call_id, err := pair.Ctl0.Call(
+		"echo",
+		[]*ARPCCallArg{NewARPCCallArgFromValue("z")},
+		false,
+		rh,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	case <-timedout:
+		t.Fatal("handler timed out")
+	case <-closed:
+		t.Fatal("handler closed")
+	case reply := <-reply_c:
+		if !uuidsEqual(reply.ReplyToId, call_id) {
+			t.Errorf("reply to %v, expected %v", reply.ReplyToId, call_id)
+		}
+	}
+}
+
+func TestARPCNodePairUnhandledCall(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	serveTestCalls(t, pair.Ctl1)
+	ctx := newTestContext(t)
+
+	unhandled := make(chan *ARPCCall, 1)
+	pair.Ctl0.OnUnhandledResultCB = func(call *ARPCCall) {
+		unhandled <- call
+	}
+
+	call_id, err := pair.Ctl0.Call("echo", nil, true, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	case reply := <-unhandled:
+		if !uuidsEqual(reply.ReplyToId, call_id) {
+			t.Errorf("reply to %v, expected %v", reply.ReplyToId, call_id)
+		}
+	}
+}
+
+func TestARPCNodePairBuffers(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	ctx := newTestContext(t)
+
+	buffer := NewARPCBufferMemObject("title", "description")
+	for _, i := range []string{"a", "b", "c"} {
+		_, err := buffer.Append(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	new_buffer := make(chan *gouuidtools.UUID, 1)
+	pair.Ctl0.OnNewBufferCB = func(buffer_id *gouuidtools.UUID) {
+		new_buffer <- buffer_id
+	}
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	case id := <-new_buffer:
+		if !uuidsEqual(id, buffer_id) {
+			t.Errorf("NewBuffer id %v, expected %v", id, buffer_id)
+		}
+	}
+
+	info, err := pair.Node0.BufferGetInfoCtx(ctx, buffer_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !uuidsEqual(info.Id, buffer_id) ||
+		info.HumanTitle != "title" ||
+		info.Mode != ARPCBufferModeObject ||
+		info.Finished {
+		t.Errorf("unexpected info: %+v", info)
+	}
+
+	count, err := pair.Node0.BufferGetItemsCountCtx(ctx, buffer_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if count != 3 {
+		t.Errorf("count %d", count)
+	}
+
+	first_spec := new(ARPCBufferItemSpecifier)
+	first_spec.SetIndex(1)
+	last_spec := new(ARPCBufferItemSpecifier)
+	last_spec.SetIndex(-1)
+
+	ids, err := pair.Node0.BufferGetItemsIdsCtx(ctx, buffer_id, first_spec, last_spec)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(ids) != 2 || ids[0] != "1" || ids[1] != "2" {
+		t.Fatalf("ids %v", ids)
+	}
+
+	items, err := pair.Node0.BufferGetItemsByIdsCtx(ctx, buffer_id, ids)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 2 || items[0].Value != "b" || items[1].Value != "c" {
+		t.Errorf("items %+v", items)
+	}
+
+	_, err = pair.Node0.BufferGetItemsByIdsCtx(ctx, buffer_id, []string{"10"})
+	if !errors.Is(err, ARPCErrNotFound) {
+		t.Errorf("missing item error: %v", err)
+	}
+
+	_, err = pair.Node0.BufferBinaryGetSizeCtx(ctx, buffer_id)
+	if !errors.Is(err, ARPCErrWrongBufferMode) {
+		t.Errorf("binary size of object buffer error: %v", err)
+	}
+
+	unknown_id, err := gouuidtools.NewUUIDFromString(
+		"00000000-0000-0000-0000-000000000001",
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	_, err = pair.Node0.BufferGetInfoCtx(ctx, unknown_id)
+	if !errors.Is(err, ARPCErrNotFound) {
+		t.Errorf("unknown buffer error: %v", err)
+	}
+}
+
+func TestARPCNodePairBinaryBuffer(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	ctx := newTestContext(t)
+
+	buffer := NewARPCBufferMemBinary("bin", "")
+	for _, i := range []string{"hello", ", ", "world"} {
+		_, err := buffer.Append([]byte(i))
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size, err := pair.Node0.BufferBinaryGetSizeCtx(ctx, buffer_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if size != 12 {
+		t.Errorf("size %d", size)
+	}
+
+	data, err := pair.Node0.BufferBinaryGetSliceCtx(ctx, buffer_id, 3, 9)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if string(data) != "lo, wo" {
+		t.Errorf("slice %q", data)
+	}
+
+	_, err = pair.Node0.BufferBinaryGetSliceCtx(ctx, buffer_id, 5, 100)
+	if err == nil {
+		t.Error("slice out of range succeeded")
+	}
+}
+
+func TestARPCNodePairBufferUpdates(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	ctx := newTestContext(t)
+
+	buffer := NewARPCBufferMemObject("updates", "")
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	updated := make(chan *gouuidtools.UUID, 10)
+	pair.Ctl0.OnBufferUpdatedCB = func(buffer_id *gouuidtools.UUID) {
+		updated <- buffer_id
+	}
+
+	err = pair.Node0.BufferSubscribeOnUpdatesNotificationCtx(ctx, buffer_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subscribed, err :=
+		pair.Node0.BufferGetIsSubscribedOnUpdatesNotificationCtx(ctx, buffer_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !subscribed {
+		t.Error("not subscribed after subscription")
+	}
+
+	_, err = buffer.Append("x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case <-ctx.Done():
+		t.Fatal(ctx.Err())
+	case id := <-updated:
+		if !uuidsEqual(id, buffer_id) {
+			t.Errorf("BufferUpdated id %v, expected %v", id, buffer_id)
+		}
+	}
+
+	err = pair.Node0.BufferUnsubscribeFromUpdatesNotificationCtx(ctx, buffer_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	subscribed, err =
+		pair.Node0.BufferGetIsSubscribedOnUpdatesNotificationCtx(ctx, buffer_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if subscribed {
+		t.Error("subscribed after unsubscription")
+	}
+}
+
+// buffer, which doesn't implement ARPCFinishNotifierI
+type testFinishableBuffer struct {
+	ARPCBufferI
+	finished int32
+}
+
+func (self *testFinishableBuffer) GetInfo() *ARPCBufferInfo {
+	ret := self.ARPCBufferI.GetInfo()
+	ret.Finished = atomic.LoadInt32(&self.finished) != 0
+	return ret
+}
+
+type testFinishedObject struct {
+	object_type ARPCObjectType
+	object_id   *gouuidtools.UUID
+}
+
+func TestARPCNodePairObjectFinished(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	ctx := newTestContext(t)
+
+	finished := make(chan testFinishedObject, 10)
+	pair.Ctl0.OnObjectFinishedCB = func(
+		object_type ARPCObjectType,
+		object_id *gouuidtools.UUID,
+	) {
+		finished <- testFinishedObject{object_type, object_id}
+	}
+
+	expect := func(object_type ARPCObjectType, object_id *gouuidtools.UUID) {
+		t.Helper()
+		select {
+		case <-ctx.Done():
+			t.Fatalf("no ObjectFinished for %s", object_type)
+		case x := <-finished:
+			if x.object_type != object_type || !uuidsEqual(x.object_id, object_id) {
+				t.Errorf(
+					"ObjectFinished for %s %v, expected %s %v",
+					x.object_type, x.object_id, object_type, object_id,
+				)
+			}
+		}
+	}
+
+	buffer := NewARPCBufferMemObject("", "")
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer.SetFinished()
+	expect(ARPCObjectTypeBuffer, buffer_id)
+
+	tr := NewARPCTransmissionMem("", "")
+	tr_id, err := pair.Ctl1.PublishTransmission(nil, tr)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = tr.Finish()
+	if err != nil {
+		t.Fatal(err)
+	}
+	expect(ARPCObjectTypeTransmission, tr_id)
+
+	// other buffers are reported by BufferChanged()
+	fb := &testFinishableBuffer{ARPCBufferI: NewARPCBufferMemObject("", "")}
+	fb_id, err := pair.Ctl1.PublishBuffer(nil, fb)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pair.Ctl1.BufferChanged(fb_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	atomic.StoreInt32(&fb.finished, 1)
+
+	for i := 0; i != 2; i++ {
+		err = pair.Ctl1.BufferChanged(fb_id)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	expect(ARPCObjectTypeBuffer, fb_id)
+
+	// already finished buffer isn't reported
+	_, err = pair.Ctl1.PublishBuffer(nil, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// make sure, that all notifications are delivered
+	_, err = pair.Node0.BufferGetInfoCtx(ctx, fb_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	select {
+	case x := <-finished:
+		t.Errorf("unexpected ObjectFinished for %s %v", x.object_type, x.object_id)
+	default:
+	}
+}
+
+// listening socket on Ctl1, each opened connection of which is echoed
+func listenTestEcho(t *testing.T, ctl *ARPCNodeCtlBasic) *gouuidtools.UUID {
+	t.Helper()
+
+	ls := NewARPCListeningSocketDialer(
+		func() (net.Conn, error) {
+			local, remote := net.Pipe()
+			go func() {
+				io.Copy(remote, remote)
+				remote.Close()
+			}()
+			return local, nil
+		},
+	)
+
+	ls_id, err := ctl.SocketListen(nil, ls)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return ls_id
+}
+
+// writes chunks to conn and checks, that same data is read back
+func checkTestEcho(t *testing.T, conn net.Conn, chunks int) {
+	t.Helper()
+
+	conn.SetDeadline(time.Now().Add(10 * time.Second))
+
+	for i := 0; i != chunks; i++ {
+		chunk := bytes.Repeat([]byte{byte('a' + i%26)}, 100+i)
+
+		_, err := conn.Write(chunk)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		buf := make([]byte, len(chunk))
+		_, err = io.ReadFull(conn, buf)
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		if !bytes.Equal(buf, chunk) {
+			t.Fatalf("chunk %d: got %q", i, buf)
+		}
+	}
+}
+
+func TestARPCNodePairSockets(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	ls_id := listenTestEcho(t, pair.Ctl1)
+
+	conn, err := pair.Ctl0.SocketDial(ls_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	checkTestEcho(t, conn, 10)
+
+	err = conn.Close()
+	if err != nil {
+		t.Error(err)
+	}
+
+	_, err = conn.Write([]byte("x"))
+	if err == nil {
+		t.Error("write to closed conn succeeded")
+	}
+}
+
+// pull mode requests are independent, so reordering and jitter don't
+// break the stream
+func TestARPCNodePairSocketsReorder(t *testing.T) {
+	pair := newTestNodePair(
+		t,
+		&ARPCNodePairOptions{
+			Latency:            time.Millisecond,
+			LatencyJitter:      2 * time.Millisecond,
+			ReorderProbability: 0.5,
+			Seed:               1,
+		},
+	)
+	ls_id := listenTestEcho(t, pair.Ctl1)
+
+	conn, err := pair.Ctl0.SocketDial(ls_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer conn.Close()
+
+	checkTestEcho(t, conn, 20)
+}
+
+func TestARPCNodePairSocketsDrop(t *testing.T) {
+	pair := newTestNodePair(
+		t,
+		&ARPCNodePairOptions{
+			DropProbability: 1,
+			Seed:            1,
+		},
+	)
+	pair.Ctl0.ResponseTimeout = 100 * time.Millisecond
+
+	ls_id := listenTestEcho(t, pair.Ctl1)
+
+	_, err := pair.Ctl0.SocketDial(ls_id)
+	if err == nil {
+		t.Fatal("dial over lossy link succeeded")
+	}
+}
+
+func TestARPCNodePairCallAndWaitCancel(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+
+	received := make(chan struct{}, 1)
+	pair.Ctl1.OnCallCB = func(call *ARPCCall) (error, error) {
+		// never replied
+		received <- struct{}{}
+		return nil, nil
+	}
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	go func() {
+		<-received
+		cancel()
+	}()
+
+	_, err := pair.Ctl0.CallAndWait(
+		ctx,
+		"hang",
+		NewARPCCallArgFromValue(NewARPCBufferMemObject("arg", "")),
+	)
+	if !errors.Is(err, context.Canceled) {
+		t.Fatalf("expected cancellation, got %v", err)
+	}
+
+	pair.Ctl0.calls_mtx.Lock()
+	calls := len(pair.Ctl0.calls)
+	pair.Ctl0.calls_mtx.Unlock()
+
+	pair.Ctl0.buffers_mtx.Lock()
+	buffers := len(pair.Ctl0.buffers)
+	pair.Ctl0.buffers_mtx.Unlock()
+
+	if calls != 0 || buffers != 0 {
+		t.Errorf("%d calls and %d buffers left after cancellation", calls, buffers)
+	}
+}
+
+func testCallCount(ctl *ARPCNodeCtlBasic) int {
+	ctl.calls_mtx.Lock()
+	defer ctl.calls_mtx.Unlock()
+	return len(ctl.calls)
+}
+
+func TestARPCNodePairCallAndWaitClosesRecords(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	serveTestCalls(t, pair.Ctl1)
+	ctx := newTestContext(t)
+
+	for _, name := range []string{"echo", "fail"} {
+		_, err := pair.Ctl0.CallAndWait(ctx, name, NewARPCCallArgFromValue(1))
+		if name == "echo" && err != nil {
+			t.Fatal(err)
+		}
+
+		if n := testCallCount(pair.Ctl0); n != 0 {
+			t.Errorf("%s: %d call records left on caller side", name, n)
+		}
+		if n := testCallCount(pair.Ctl1); n != 0 {
+			t.Errorf("%s: %d call records left on replier side", name, n)
+		}
+	}
+
+	// reply, owning buffer, is kept, so buffer can be used
+	buffer := NewARPCBufferMemObject("", "")
+	_, err := buffer.Append("x")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pair.Ctl1.OnCallCB = func(call *ARPCCall) (error, error) {
+		return nil, pair.Ctl1.Reply(call.CallId, NewARPCCallArgFromValue(buffer))
+	}
+
+	reply, err := pair.Ctl0.CallAndWait(ctx, "buffer")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(reply.Args) != 1 || reply.Args[0].Buffer == nil {
+		t.Fatalf("unexpected reply: %+v", reply)
+	}
+
+	_, err = pair.Node0.BufferGetInfoCtx(ctx, reply.Args[0].Buffer.Id)
+	if err != nil {
+		t.Error(err)
+	}
+}
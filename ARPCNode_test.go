@@ -0,0 +1,164 @@
+package goarpcsolution
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+func TestARPCNodeCancelledRequest(t *testing.T) {
+	var message_errors int32
+
+	pair := newTestNodePair(
+		t,
+		&ARPCNodePairOptions{
+			Latency: 100 * time.Millisecond,
+			OnMessageErrorsCB: func(node_index int, err_proto error, err error) {
+				atomic.AddInt32(&message_errors, 1)
+			},
+		},
+	)
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, NewARPCBufferMemObject("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 10*time.Millisecond)
+	defer cancel()
+
+	_, err = pair.Node0.BufferGetInfoCtx(ctx, buffer_id)
+	if !errors.Is(err, ErrARPCTimeout) {
+		t.Fatalf("expected timeout, got %v", err)
+	}
+
+	if n := pair.Node0.GetPendingRequestsCount(); n != 0 {
+		t.Errorf("%d pending requests after cancellation", n)
+	}
+
+	// late response is dropped silently
+	time.Sleep(300 * time.Millisecond)
+
+	if n := atomic.LoadInt32(&message_errors); n != 0 {
+		t.Errorf("%d message errors", n)
+	}
+
+	_, err = pair.Node0.BufferGetInfoCtx(newTestContext(t), buffer_id)
+	if err != nil {
+		t.Error(err)
+	}
+}
+
+func TestARPCNodeCloseFinishesRequests(t *testing.T) {
+	pair := newTestNodePair(t, &ARPCNodePairOptions{DropProbability: 1})
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, NewARPCBufferMemObject("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	sent := make(chan struct{}, 1)
+	push := pair.Node0.PushMessageToOutsideCB
+	pair.Node0.PushMessageToOutsideCB = func(data []byte) error {
+		sent <- struct{}{}
+		return push(data)
+	}
+
+	res := make(chan error, 1)
+	go func() {
+		_, err := pair.Node0.BufferGetInfoCtx(newTestContext(t), buffer_id)
+		res <- err
+	}()
+
+	<-sent
+	pair.Node0.Close()
+
+	select {
+	case err := <-res:
+		if !errors.Is(err, ErrARPCClosed) {
+			t.Errorf("expected closed error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("request isn't finished by Close()")
+	}
+}
+
+func TestARPCNodeClosedRequest(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, NewARPCBufferMemObject("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pair.Node0.Close()
+
+	_, err = pair.Node0.BufferGetInfoCtx(newTestContext(t), buffer_id)
+	if !errors.Is(err, ErrARPCClosed) {
+		t.Errorf("expected closed error, got %v", err)
+	}
+
+	_, _, closed, _, err := pair.Node0.BufferGetInfo(buffer_id, time.Second)
+	if !closed || err != nil {
+		t.Errorf("BufferGetInfo(): closed %v, err %v", closed, err)
+	}
+
+	if n := pair.Node0.GetPendingRequestsCount(); n != 0 {
+		t.Errorf("%d pending requests", n)
+	}
+
+	err = pair.Node0.BufferUpdated(buffer_id)
+	if !errors.Is(err, ErrARPCClosed) {
+		t.Errorf("expected closed error from notification, got %v", err)
+	}
+}
+
+func TestARPCNodeClosedPush(t *testing.T) {
+	ctl, _ := newTestCtl(t)
+
+	node := NewARPCNode(ctl)
+	node.PushMessageToOutsideCB = func(data []byte) error { return nil }
+	node.Close()
+
+	_, err := node.PushMessageFromOutside([]byte(`{"jsonrpc":"2.0"}`))
+	if !errors.Is(err, ErrARPCClosed) {
+		t.Errorf("expected closed error, got %v", err)
+	}
+}
+
+// node is closed, while messages are delivered to it
+func TestARPCNodePairCloseUnderLoad(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, NewARPCBufferMemObject("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	done := make(chan struct{})
+	for i := 0; i != 4; i++ {
+		go func() {
+			defer func() { done <- struct{}{} }()
+			for {
+				_, err := pair.Node0.BufferGetInfoCtx(newTestContext(t), buffer_id)
+				if errors.Is(err, ErrARPCClosed) {
+					return
+				}
+			}
+		}()
+	}
+
+	time.Sleep(20 * time.Millisecond)
+	pair.Node1.Close()
+	pair.Node0.Close()
+
+	for i := 0; i != 4; i++ {
+		select {
+		case <-done:
+		case <-time.After(5 * time.Second):
+			t.Fatal("request isn't finished by Close()")
+		}
+	}
+}
@@ -0,0 +1,343 @@
+package goarpcsolution
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/AnimusPEXUS/gouuidtools"
+)
+
+// count of item ids in one BufferGetItemsByIds request
+const ARPC_BUFFER_BATCH_DEFAULT_SIZE = 100
+
+// count of BufferGetItemsByIds requests in flight
+const ARPC_BUFFER_BATCH_DEFAULT_CONCURRENCY = 4
+
+// options of pipelined retrieval of buffer items. nil means defaults
+type ARPCBufferBatchOptions struct {
+	// 0 - ARPC_BUFFER_BATCH_DEFAULT_SIZE
+	BatchSize int
+
+	// 0 - ARPC_BUFFER_BATCH_DEFAULT_CONCURRENCY
+	Concurrency int
+}
+
+func (self *ARPCBufferBatchOptions) batchSize() int {
+	if self == nil || self.BatchSize <= 0 {
+		return ARPC_BUFFER_BATCH_DEFAULT_SIZE
+	}
+	return self.BatchSize
+}
+
+func (self *ARPCBufferBatchOptions) concurrency() int {
+	if self == nil || self.Concurrency <= 0 {
+		return ARPC_BUFFER_BATCH_DEFAULT_CONCURRENCY
+	}
+	return self.Concurrency
+}
+
+// splits ids into batches of size items (last one may be shorter)
+func bufferSplitIds(ids []string, size int) [][]string {
+	ret := make([][]string, 0, (len(ids)+size-1)/size)
+	for len(ids) != 0 {
+		n := size
+		if n > len(ids) {
+			n = len(ids)
+		}
+		ret = append(ret, ids[:n])
+		ids = ids[n:]
+	}
+	return ret
+}
+
+// requests items of all ids: remote node may return less items, than
+// requested (see ARPCNodeCtlI.BufferGetItemsByIds())
+func (self *ARPCNode) bufferGetItemsByIdsAll(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	ids []string,
+) ([]*ARPCBufferItem, error) {
+	ret := make([]*ARPCBufferItem, 0, len(ids))
+
+	for len(ids) != 0 {
+		items, err := self.BufferGetItemsByIdsCtx(ctx, buffer_id, ids)
+		if err != nil {
+			return nil, err
+		}
+
+		if len(items) == 0 || len(items) > len(ids) {
+			return nil, errors.New("remote returned invalid count of items")
+		}
+
+		ret = append(ret, items...)
+		ids = ids[len(items):]
+	}
+
+	return ret, nil
+}
+
+// like BufferGetItemsByIdsCtx(), but ids are requested by batches, with
+// several requests in flight. items are returned in order of ids.
+// on first error, requests which are still in flight are cancelled
+func (self *ARPCNode) BufferGetItemsByIdsBatchedCtx(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	ids []string,
+	opts *ARPCBufferBatchOptions,
+) (
+	buffer_items []*ARPCBufferItem,
+	err error,
+) {
+	batches := bufferSplitIds(ids, opts.batchSize())
+	results := make([][]*ARPCBufferItem, len(batches))
+
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	var (
+		wg        sync.WaitGroup
+		err_mtx   sync.Mutex
+		first_err error
+	)
+
+	sem := make(chan struct{}, opts.concurrency())
+
+	for i, batch := range batches {
+		select {
+		case sem <- struct{}{}:
+		case <-ctx.Done():
+		}
+
+		if ctx.Err() != nil {
+			break
+		}
+
+		wg.Add(1)
+		go func(i int, batch []string) {
+			defer wg.Done()
+			defer func() { <-sem }()
+
+			items, err := self.bufferGetItemsByIdsAll(ctx, buffer_id, batch)
+			if err != nil {
+				err_mtx.Lock()
+				if first_err == nil {
+					first_err = err
+				}
+				err_mtx.Unlock()
+				cancel()
+				return
+			}
+
+			results[i] = items
+		}(i, batch)
+	}
+
+	wg.Wait()
+
+	if first_err == nil && ctx.Err() != nil {
+		first_err = ctx.Err()
+	}
+
+	if first_err != nil {
+		return nil, first_err
+	}
+
+	buffer_items = make([]*ARPCBufferItem, 0, len(ids))
+	for _, i := range results {
+		buffer_items = append(buffer_items, i...)
+	}
+
+	return buffer_items, nil
+}
+
+// walks remote buffer from first_spec to it's end. item ids are
+// requested by pages (see ARPC_BUFFER_ITEMS_IDS_MAX_COUNT), next page -
+// after last id of previous one, when batches of previous page are
+// requested. items are requested by batches, with up to
+// opts.Concurrency batches requested or waiting ahead of reader.
+// items, added to buffer during walk, may be walked too. if last item
+// of page is removed from buffer before next page is requested, Next()
+// returns error
+type ARPCRemoteBufferIterator struct {
+	node       *ARPCNode
+	buffer_id  *gouuidtools.UUID
+	first_spec *ARPCBufferItemSpecifier
+	opts       *ARPCBufferBatchOptions
+
+	// bounds all requests of iterator. cancelled by Close()
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// all pages of ids are received
+	ids_done bool
+	// last id of last received page
+	last_id string
+	batches [][]string
+
+	// requested batches in order of ids
+	pending []*xARPCRemoteBufferBatch
+
+	items []*ARPCBufferItem
+
+	err error
+}
+
+type xARPCRemoteBufferBatch struct {
+	done  chan struct{}
+	items []*ARPCBufferItem
+	err   error
+}
+
+// first_spec nil - first item of buffer.
+// Next() isn't safe for concurrent use
+func (self *ARPCNode) BufferIterate(
+	buffer_id *gouuidtools.UUID,
+	first_spec *ARPCBufferItemSpecifier,
+	opts *ARPCBufferBatchOptions,
+) *ARPCRemoteBufferIterator {
+	ret := &ARPCRemoteBufferIterator{
+		node:       self,
+		buffer_id:  buffer_id,
+		first_spec: first_spec,
+		opts:       opts,
+	}
+	ret.ctx, ret.cancel = context.WithCancel(context.Background())
+	return ret
+}
+
+// returns io.EOF after last item. ctx bounds only this call: requests
+// started by it continue in background until Close()
+func (self *ARPCRemoteBufferIterator) Next(
+	ctx context.Context,
+) (*ARPCBufferItem, error) {
+	for len(self.items) == 0 {
+		if self.err != nil {
+			return nil, self.err
+		}
+
+		if len(self.batches) == 0 && !self.ids_done {
+			err := self.requestIds(ctx)
+			if err != nil {
+				return nil, err
+			}
+		}
+
+		self.fill()
+
+		if len(self.pending) == 0 {
+			self.err = io.EOF
+			continue
+		}
+
+		b := self.pending[0]
+
+		select {
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		case <-b.done:
+		}
+
+		self.pending = self.pending[1:]
+
+		if b.err != nil {
+			self.err = b.err
+			self.cancel()
+			continue
+		}
+
+		self.items = b.items
+	}
+
+	ret := self.items[0]
+	self.items = self.items[1:]
+
+	return ret, nil
+}
+
+// cancels requests in flight. later Next() calls return error
+func (self *ARPCRemoteBufferIterator) Close() {
+	self.cancel()
+	if self.err == nil {
+		self.err = context.Canceled
+	}
+	self.pending = nil
+	self.items = nil
+}
+
+// requests next page of ids
+func (self *ARPCRemoteBufferIterator) requestIds(ctx context.Context) error {
+	first_spec := self.first_spec
+	switch {
+	case self.last_id != "":
+		first_spec = new(ARPCBufferItemSpecifier)
+		first_spec.SetStringVal(self.last_id)
+	case first_spec == nil:
+		first_spec = new(ARPCBufferItemSpecifier)
+		first_spec.SetIndex(0)
+	}
+
+	last_spec := new(ARPCBufferItemSpecifier)
+	last_spec.SetIndex(-1)
+
+	// both contexts must be able to stop request
+	req_ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	go func() {
+		select {
+		case <-self.ctx.Done():
+			cancel()
+		case <-req_ctx.Done():
+		}
+	}()
+
+	ids, err := self.node.BufferGetItemsIdsCtx(
+		req_ctx,
+		self.buffer_id,
+		first_spec,
+		last_spec,
+	)
+	if err != nil {
+		// cancellation of ctx doesn't break iterator
+		if ctx.Err() == nil {
+			self.err = err
+		}
+		return err
+	}
+
+	// page starts with last id of previous one
+	if self.last_id != "" && len(ids) != 0 && ids[0] == self.last_id {
+		ids = ids[1:]
+	}
+
+	if len(ids) == 0 {
+		self.ids_done = true
+		return nil
+	}
+
+	self.last_id = ids[len(ids)-1]
+	self.batches = bufferSplitIds(ids, self.opts.batchSize())
+
+	return nil
+}
+
+// requests next batches, while there is room for them
+func (self *ARPCRemoteBufferIterator) fill() {
+	for len(self.pending) < self.opts.concurrency() && len(self.batches) != 0 {
+		ids := self.batches[0]
+		self.batches = self.batches[1:]
+
+		b := &xARPCRemoteBufferBatch{done: make(chan struct{})}
+		self.pending = append(self.pending, b)
+
+		go func() {
+			defer close(b.done)
+			b.items, b.err = self.node.bufferGetItemsByIdsAll(
+				self.ctx,
+				self.buffer_id,
+				ids,
+			)
+		}()
+	}
+}
@@ -0,0 +1,468 @@
+package goarpcsolution
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"time"
+
+	"github.com/AnimusPEXUS/gouuidtools"
+)
+
+var ErrARPCBufferRemoved = errors.New("arpc: followed buffer removed")
+
+// retry interval of failed renewal of followed buffer's lease
+const ARPC_REMOTE_BUFFER_FOLLOWER_TOUCH_RETRY = 10 * time.Second
+
+// options of ARPCNode.BufferFollow(). nil means defaults
+type ARPCRemoteBufferFollowerOptions struct {
+	// first item to deliver. nil - first item of buffer
+	FirstSpec *ARPCBufferItemSpecifier
+
+	// capacity of Items() channel
+	ChanSize int
+
+	// retrieval of new items
+	Batch *ARPCBufferBatchOptions
+}
+
+// delivers items of remote buffer in order, as they are added to it.
+//
+// follower subscribes on buffer's updates and, on each BufferUpdated
+// notification, requests items after last delivered one (by it's id).
+// notifications, which come during request, are coalesced into one
+// more request, so missed notifications don't lose items.
+// if last delivered item is removed from buffer (for instance, if buffer
+// is trimmed from head), follower continues from it's time, so items
+// times must not decrease in such buffers.
+//
+// Items() is closed when buffer becomes finished (after it's last items
+// are delivered), when buffer is removed, on error or on Stop(). Err()
+// tells the reason. buffer's info is checked after each request, so
+// finishing is noticed even if remote node doesn't send ObjectFinished.
+//
+// follower renews buffer's lease (Touch() after half of TTL), so idle
+// buffer doesn't expire while it's followed
+type ARPCRemoteBufferFollower struct {
+	node      *ARPCNode
+	buffer_id *gouuidtools.UUID
+	opts      ARPCRemoteBufferFollowerOptions
+
+	items chan *ARPCBufferItem
+
+	// capacity 1: notifications coalesce
+	wake chan struct{}
+	done chan struct{}
+
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	mtx      sync.Mutex
+	finished bool
+	removed  bool
+	err      error
+
+	// position after last delivered item. last_time_ids - ids of
+	// delivered items with time equal to last_time
+	have_last     bool
+	last_id       string
+	last_time     time.Time
+	last_time_ids map[string]struct{}
+}
+
+// starts following of remote buffer. subscription on buffer's updates
+// is cancelled, when last follower of buffer stops
+func (self *ARPCNode) BufferFollow(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	opts *ARPCRemoteBufferFollowerOptions,
+) (*ARPCRemoteBufferFollower, error) {
+	ret := &ARPCRemoteBufferFollower{
+		node:          self,
+		buffer_id:     buffer_id,
+		wake:          make(chan struct{}, 1),
+		done:          make(chan struct{}),
+		last_time_ids: make(map[string]struct{}),
+	}
+
+	if opts != nil {
+		ret.opts = *opts
+	}
+
+	ret.items = make(chan *ARPCBufferItem, ret.opts.ChanSize)
+	ret.ctx, ret.cancel = context.WithCancel(context.Background())
+
+	// registered before subscription, so no notification is missed
+	self.bufferFollowerAdd(ret)
+
+	err := self.BufferSubscribeOnUpdatesNotificationCtx(ctx, buffer_id)
+	if err == nil {
+		var info *ARPCBufferInfo
+		info, err = self.BufferGetInfoCtx(ctx, buffer_id)
+		if err == nil && info.Finished {
+			ret.setFinished()
+		}
+	}
+
+	if err != nil {
+		ret.cancel()
+		ret.unregister()
+		return nil, err
+	}
+
+	ret.wakeUp()
+
+	go ret.run()
+
+	return ret, nil
+}
+
+func (self *ARPCRemoteBufferFollower) Items() <-chan *ARPCBufferItem {
+	return self.items
+}
+
+// reason of Items() closing: nil if buffer finished or Stop() called,
+// ErrARPCBufferRemoved or error of request
+func (self *ARPCRemoteBufferFollower) Err() error {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	return self.err
+}
+
+// stops follower and waits for Items() to be closed. items, which are
+// not received yet, are dropped
+func (self *ARPCRemoteBufferFollower) Stop() {
+	self.cancel()
+	<-self.done
+}
+
+func (self *ARPCRemoteBufferFollower) wakeUp() {
+	select {
+	case self.wake <- struct{}{}:
+	default:
+	}
+}
+
+func (self *ARPCRemoteBufferFollower) setFinished() {
+	self.mtx.Lock()
+	self.finished = true
+	self.mtx.Unlock()
+	self.wakeUp()
+}
+
+func (self *ARPCRemoteBufferFollower) setRemoved() {
+	self.mtx.Lock()
+	self.removed = true
+	self.mtx.Unlock()
+	self.wakeUp()
+}
+
+func (self *ARPCRemoteBufferFollower) state() (finished, removed bool) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	return self.finished, self.removed
+}
+
+func (self *ARPCRemoteBufferFollower) run() {
+	var err error
+
+	touch := time.NewTimer(0)
+
+	defer func() {
+		touch.Stop()
+
+		self.mtx.Lock()
+		self.err = err
+		self.mtx.Unlock()
+
+		self.unregister()
+
+		close(self.items)
+		close(self.done)
+	}()
+
+	for {
+		select {
+		case <-self.ctx.Done():
+			return
+		case <-touch.C:
+			// may be chosen after Stop() or node's Close() as well
+			var interval time.Duration
+			interval, err = self.touch()
+			if err != nil {
+				if self.ctx.Err() != nil {
+					err = nil
+				}
+				return
+			}
+			if interval > 0 {
+				touch.Reset(interval)
+			}
+			continue
+		case <-self.wake:
+		}
+
+		// removed buffer can't be requested: items, which wasn't
+		// fetched before removal, are lost
+		finished, removed := self.state()
+		if removed {
+			err = ErrARPCBufferRemoved
+			return
+		}
+
+		err = self.fetch()
+		if err == nil && !finished {
+			err = self.checkFinished()
+		}
+		if err != nil {
+			if self.ctx.Err() != nil {
+				err = nil
+			}
+			return
+		}
+
+		if finished {
+			return
+		}
+	}
+}
+
+// renews buffer's lease. returns interval until next renewal: 0 if
+// buffer is pinned. failed renewal is retried, unless follower is
+// stopped, node is closed or buffer is removed
+func (self *ARPCRemoteBufferFollower) touch() (time.Duration, error) {
+	ttl, err := self.node.TouchCtx(self.ctx, ARPCObjectTypeBuffer, self.buffer_id)
+	if err != nil {
+		switch {
+		case errors.Is(err, ARPCErrNotFound) || errors.Is(err, ARPCErrExpired):
+			return 0, ErrARPCBufferRemoved
+		case self.ctx.Err() != nil || errors.Is(err, ErrARPCClosed):
+			return 0, err
+		}
+		return ARPC_REMOTE_BUFFER_FOLLOWER_TOUCH_RETRY, nil
+	}
+	return ttl / 2, nil
+}
+
+// wakes follower once more, if buffer is finished
+func (self *ARPCRemoteBufferFollower) checkFinished() error {
+	info, err := self.node.BufferGetInfoCtx(self.ctx, self.buffer_id)
+	if err != nil {
+		return err
+	}
+
+	if info.Finished {
+		self.setFinished()
+	}
+
+	return nil
+}
+
+// requests and delivers items after last delivered one. ids are
+// returned by pages, so it continues, while pages have new items
+func (self *ARPCRemoteBufferFollower) fetch() error {
+	for {
+		more, err := self.fetchPage()
+		if err != nil || !more {
+			return err
+		}
+	}
+}
+
+// more is true, if items are delivered, so more of them may follow
+func (self *ARPCRemoteBufferFollower) fetchPage() (more bool, err error) {
+	last_spec := new(ARPCBufferItemSpecifier)
+	last_spec.SetIndex(-1)
+
+	first_spec := new(ARPCBufferItemSpecifier)
+	switch {
+	case !self.have_last && self.opts.FirstSpec != nil:
+		first_spec = self.opts.FirstSpec
+	case !self.have_last:
+		first_spec.SetIndex(0)
+	default:
+		first_spec.SetStringVal(self.last_id)
+	}
+
+	ids, err := self.node.BufferGetItemsIdsCtx(
+		self.ctx,
+		self.buffer_id,
+		first_spec,
+		last_spec,
+	)
+
+	if self.have_last && errors.Is(err, ARPCErrNotFound) {
+		// last item is gone: continue from it's time
+		first_spec.SetTime(self.last_time)
+		ids, err = self.node.BufferGetItemsIdsCtx(
+			self.ctx,
+			self.buffer_id,
+			first_spec,
+			last_spec,
+		)
+	}
+
+	if err != nil {
+		return false, err
+	}
+
+	ids = self.dropDelivered(ids)
+	if len(ids) == 0 {
+		return false, nil
+	}
+
+	items, err := self.node.BufferGetItemsByIdsBatchedCtx(
+		self.ctx,
+		self.buffer_id,
+		ids,
+		self.opts.Batch,
+	)
+	if err != nil {
+		if errors.Is(err, ARPCErrNotFound) {
+			// items removed after ids are received. retry from same
+			// position
+			self.wakeUp()
+			return false, nil
+		}
+		return false, err
+	}
+
+	for _, i := range items {
+		select {
+		case <-self.ctx.Done():
+			return false, self.ctx.Err()
+		case self.items <- i:
+		}
+
+		self.advance(i)
+	}
+
+	return true, nil
+}
+
+// drops ids of items, which are delivered already: last item (returned
+// first on continuation by id) and items with same time as it (on
+// continuation by time)
+func (self *ARPCRemoteBufferFollower) dropDelivered(ids []string) []string {
+	if !self.have_last {
+		return ids
+	}
+
+	ret := make([]string, 0, len(ids))
+	for _, i := range ids {
+		if _, ok := self.last_time_ids[i]; ok {
+			continue
+		}
+		ret = append(ret, i)
+	}
+	return ret
+}
+
+func (self *ARPCRemoteBufferFollower) advance(item *ARPCBufferItem) {
+	if !self.have_last || !item.ItemTime.Equal(self.last_time) {
+		self.last_time = item.ItemTime
+		self.last_time_ids = make(map[string]struct{})
+	}
+
+	self.have_last = true
+	self.last_id = item.ItemId
+	self.last_time_ids[item.ItemId] = struct{}{}
+}
+
+// removes follower from node. unsubscribes from buffer updates, if it
+// was last follower of buffer
+func (self *ARPCRemoteBufferFollower) unregister() {
+	last := self.node.bufferFollowerRemove(self)
+	if !last {
+		return
+	}
+
+	finished, removed := self.state()
+	if finished || removed {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(
+		context.Background(),
+		self.node.ctxResponseTimeout(),
+	)
+	defer cancel()
+
+	self.node.BufferUnsubscribeFromUpdatesNotificationCtx(ctx, self.buffer_id)
+}
+
+func (self *ARPCNode) bufferFollowerAdd(f *ARPCRemoteBufferFollower) {
+	self.followers_mtx.Lock()
+	defer self.followers_mtx.Unlock()
+
+	key := f.buffer_id.Format()
+	self.followers[key] = append(self.followers[key], f)
+}
+
+// true if f was last follower of it's buffer
+func (self *ARPCNode) bufferFollowerRemove(f *ARPCRemoteBufferFollower) bool {
+	self.followers_mtx.Lock()
+	defer self.followers_mtx.Unlock()
+
+	key := f.buffer_id.Format()
+	list := self.followers[key]
+
+	for i, x := range list {
+		if x == f {
+			list = append(list[:i], list[i+1:]...)
+			break
+		}
+	}
+
+	if len(list) == 0 {
+		delete(self.followers, key)
+		return true
+	}
+
+	self.followers[key] = list
+	return false
+}
+
+func (self *ARPCNode) bufferFollowersGet(
+	buffer_id *gouuidtools.UUID,
+) []*ARPCRemoteBufferFollower {
+	self.followers_mtx.Lock()
+	defer self.followers_mtx.Unlock()
+
+	list := self.followers[buffer_id.Format()]
+	ret := make([]*ARPCRemoteBufferFollower, len(list))
+	copy(ret, list)
+	return ret
+}
+
+// called on BufferUpdated, ObjectFinished and ObjectRemoved notifications
+func (self *ARPCNode) bufferFollowersNotify(
+	buffer_id *gouuidtools.UUID,
+	finished bool,
+	removed bool,
+) {
+	for _, i := range self.bufferFollowersGet(buffer_id) {
+		switch {
+		case removed:
+			i.setRemoved()
+		case finished:
+			i.setFinished()
+		default:
+			i.wakeUp()
+		}
+	}
+}
+
+// on node close
+func (self *ARPCNode) bufferFollowersStop() {
+	self.followers_mtx.Lock()
+	list := make([]*ARPCRemoteBufferFollower, 0)
+	for _, i := range self.followers {
+		list = append(list, i...)
+	}
+	self.followers_mtx.Unlock()
+
+	for _, i := range list {
+		i.cancel()
+	}
+}
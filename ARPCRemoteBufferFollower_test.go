@@ -0,0 +1,142 @@
+package goarpcsolution
+
+import (
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// receives items from follower until Items() is closed
+func collectTestFollower(
+	t *testing.T,
+	f *ARPCRemoteBufferFollower,
+	timeout time.Duration,
+) []any {
+	t.Helper()
+
+	ret := make([]any, 0)
+	deadline := time.After(timeout)
+
+	for {
+		select {
+		case <-deadline:
+			f.Stop()
+			t.Fatalf("follower isn't stopped. items: %v", ret)
+		case i, ok := <-f.Items():
+			if !ok {
+				return ret
+			}
+			ret = append(ret, i.Value)
+		}
+	}
+}
+
+// owner doesn't send ObjectFinished, only BufferUpdated
+func TestARPCRemoteBufferFollowerFinishedUnnotified(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	ctx := newTestContext(t)
+
+	mem := NewARPCBufferMemObject("", "")
+	buffer := &testFinishableBuffer{ARPCBufferI: mem}
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := pair.Node0.BufferFollow(ctx, buffer_id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	for _, i := range []string{"a", "b"} {
+		_, err = mem.Append(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	atomic.StoreInt32(&buffer.finished, 1)
+
+	err = pair.Node1.BufferUpdated(buffer_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := collectTestFollower(t, f, 5*time.Second)
+	if len(items) != 2 || items[0] != "a" || items[1] != "b" {
+		t.Errorf("items %v", items)
+	}
+
+	if f.Err() != nil {
+		t.Error(f.Err())
+	}
+}
+
+func TestARPCRemoteBufferFollowerRenewsLease(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	ctx := newTestContext(t)
+
+	buffer := NewARPCBufferMemObject("", "")
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	err = pair.Ctl1.SetObjectTTL(ARPCObjectTypeBuffer, buffer_id, 200*time.Millisecond)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := pair.Node0.BufferFollow(ctx, buffer_id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// idle for several TTLs
+	time.Sleep(time.Second)
+
+	_, err = buffer.Append("x")
+	if err != nil {
+		t.Fatal(err)
+	}
+	buffer.SetFinished()
+
+	items := collectTestFollower(t, f, 5*time.Second)
+	if len(items) != 1 || items[0] != "x" {
+		t.Errorf("items %v", items)
+	}
+
+	if f.Err() != nil {
+		t.Error(f.Err())
+	}
+}
+
+func TestARPCRemoteBufferFollowerNodeClosed(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	ctx := newTestContext(t)
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, NewARPCBufferMemObject("", ""))
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := pair.Node0.BufferFollow(ctx, buffer_id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	pair.Node0.Close()
+
+	collectTestFollower(t, f, 5*time.Second)
+
+	if err := f.Err(); err != nil {
+		t.Errorf("Err() returned %v", err)
+	}
+
+	// timer may fire after Close(): renewal isn't retried
+	interval, err := f.touch()
+	if err == nil {
+		t.Errorf("touch() after Close() is retried in %v", interval)
+	}
+}
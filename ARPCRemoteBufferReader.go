@@ -0,0 +1,279 @@
+package goarpcsolution
+
+import (
+	"context"
+	"errors"
+	"io"
+	"sync"
+
+	"github.com/AnimusPEXUS/gouuidtools"
+)
+
+// bytes requested by one BufferBinaryGetSlice
+const ARPC_REMOTE_BINARY_READER_CHUNK_SIZE = 64 * 1024
+
+var _ io.ReadSeekCloser = &ARPCRemoteBinaryReader{}
+var _ io.ReaderAt = &ARPCRemoteBinaryReader{}
+
+// options of ARPCNode.BufferBinaryOpen(). nil means defaults
+type ARPCRemoteBinaryReaderOptions struct {
+	// 0 - ARPC_REMOTE_BINARY_READER_CHUNK_SIZE. can't be more than
+	// ARPC_BUFFER_BINARY_MAX_SLICE_SIZE
+	ChunkSize int
+
+	// count of chunks, requested ahead of Read() position. 0 - no
+	// read-ahead
+	ReadAhead int
+}
+
+// reads remote buffer in ARPCBufferModeBinary mode by chunks. chunks
+// are cached: Read() requests next opts.ReadAhead chunks in background,
+// cache keeps up to opts.ReadAhead+2 chunks.
+//
+// size is requested once, on opening, so buffer must not change while
+// it's read. Read() and Seek() are not safe for concurrent use, ReadAt()
+// is (as io.ReaderAt requires)
+type ARPCRemoteBinaryReader struct {
+	node       *ARPCNode
+	buffer_id  *gouuidtools.UUID
+	chunk_size int
+	read_ahead int
+
+	size int64
+
+	// bounds all requests. cancelled by Close()
+	ctx    context.Context
+	cancel context.CancelFunc
+
+	// position of Read()
+	pos int64
+
+	mtx    sync.Mutex
+	chunks map[int64]*xARPCRemoteBinaryChunk
+	// chunk indexes in order of their request, for eviction
+	order []int64
+}
+
+type xARPCRemoteBinaryChunk struct {
+	done chan struct{}
+	data []byte
+	err  error
+}
+
+func (self *ARPCNode) BufferBinaryOpen(
+	ctx context.Context,
+	buffer_id *gouuidtools.UUID,
+	opts *ARPCRemoteBinaryReaderOptions,
+) (*ARPCRemoteBinaryReader, error) {
+	size, err := self.BufferBinaryGetSizeCtx(ctx, buffer_id)
+	if err != nil {
+		return nil, err
+	}
+
+	ret := &ARPCRemoteBinaryReader{
+		node:       self,
+		buffer_id:  buffer_id,
+		chunk_size: ARPC_REMOTE_BINARY_READER_CHUNK_SIZE,
+		size:       int64(size),
+		chunks:     make(map[int64]*xARPCRemoteBinaryChunk),
+	}
+
+	if opts != nil {
+		if opts.ChunkSize > 0 {
+			ret.chunk_size = opts.ChunkSize
+		}
+		if ret.chunk_size > ARPC_BUFFER_BINARY_MAX_SLICE_SIZE {
+			ret.chunk_size = ARPC_BUFFER_BINARY_MAX_SLICE_SIZE
+		}
+		if opts.ReadAhead > 0 {
+			ret.read_ahead = opts.ReadAhead
+		}
+	}
+
+	ret.ctx, ret.cancel = context.WithCancel(context.Background())
+
+	return ret, nil
+}
+
+func (self *ARPCRemoteBinaryReader) GetBufferId() *gouuidtools.UUID {
+	return self.buffer_id
+}
+
+// size of buffer at opening
+func (self *ARPCRemoteBinaryReader) Size() int64 {
+	return self.size
+}
+
+func (self *ARPCRemoteBinaryReader) Read(p []byte) (n int, err error) {
+	if len(p) == 0 {
+		return 0, nil
+	}
+
+	if self.pos >= self.size {
+		return 0, io.EOF
+	}
+
+	chunk_index := self.pos / int64(self.chunk_size)
+
+	for i := 1; i <= self.read_ahead; i++ {
+		if (chunk_index+int64(i))*int64(self.chunk_size) >= self.size {
+			break
+		}
+		self.getChunk(chunk_index + int64(i))
+	}
+
+	// not more than one chunk per call: rest may be not fetched yet
+	chunk_end := (chunk_index + 1) * int64(self.chunk_size)
+	if int64(len(p)) > chunk_end-self.pos {
+		p = p[:chunk_end-self.pos]
+	}
+
+	n, err = self.ReadAt(p, self.pos)
+	self.pos += int64(n)
+
+	if err == io.EOF && n != 0 {
+		err = nil
+	}
+
+	return n, err
+}
+
+func (self *ARPCRemoteBinaryReader) ReadAt(p []byte, off int64) (n int, err error) {
+	if off < 0 {
+		return 0, errors.New("negative offset")
+	}
+
+	for n != len(p) {
+		pos := off + int64(n)
+		if pos >= self.size {
+			return n, io.EOF
+		}
+
+		chunk_index := pos / int64(self.chunk_size)
+
+		data, err := self.waitChunk(self.getChunk(chunk_index))
+		if err != nil {
+			self.dropChunk(chunk_index)
+			return n, err
+		}
+
+		chunk_off := int(pos - chunk_index*int64(self.chunk_size))
+		if chunk_off >= len(data) {
+			// buffer was shrunk
+			return n, io.ErrUnexpectedEOF
+		}
+
+		n += copy(p[n:], data[chunk_off:])
+	}
+
+	return n, nil
+}
+
+func (self *ARPCRemoteBinaryReader) Seek(offset int64, whence int) (int64, error) {
+	var pos int64
+
+	switch whence {
+	default:
+		return 0, errors.New("invalid whence")
+	case io.SeekStart:
+		pos = offset
+	case io.SeekCurrent:
+		pos = self.pos + offset
+	case io.SeekEnd:
+		pos = self.size + offset
+	}
+
+	if pos < 0 {
+		return 0, errors.New("negative position")
+	}
+
+	self.pos = pos
+
+	return pos, nil
+}
+
+// cancels requests in flight and drops cache
+func (self *ARPCRemoteBinaryReader) Close() error {
+	self.cancel()
+
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	self.chunks = make(map[int64]*xARPCRemoteBinaryChunk)
+	self.order = nil
+
+	return nil
+}
+
+// returns cached chunk or starts it's request
+func (self *ARPCRemoteBinaryReader) getChunk(
+	chunk_index int64,
+) *xARPCRemoteBinaryChunk {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if c, ok := self.chunks[chunk_index]; ok {
+		return c
+	}
+
+	c := &xARPCRemoteBinaryChunk{done: make(chan struct{})}
+
+	if self.ctx.Err() != nil {
+		c.err = self.ctx.Err()
+		close(c.done)
+		return c
+	}
+
+	self.chunks[chunk_index] = c
+	self.order = append(self.order, chunk_index)
+
+	for len(self.order) > self.read_ahead+2 {
+		delete(self.chunks, self.order[0])
+		self.order = self.order[1:]
+	}
+
+	start := chunk_index * int64(self.chunk_size)
+	end := start + int64(self.chunk_size)
+	if end > self.size {
+		end = self.size
+	}
+
+	go func() {
+		defer close(c.done)
+
+		c.data, c.err = self.node.BufferBinaryGetSliceCtx(
+			self.ctx,
+			self.buffer_id,
+			int(start),
+			int(end),
+		)
+	}()
+
+	return c
+}
+
+func (self *ARPCRemoteBinaryReader) waitChunk(
+	c *xARPCRemoteBinaryChunk,
+) ([]byte, error) {
+	<-c.done
+	return c.data, c.err
+}
+
+// failed chunks are dropped, so they could be requested again
+func (self *ARPCRemoteBinaryReader) dropChunk(chunk_index int64) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if _, ok := self.chunks[chunk_index]; !ok {
+		return
+	}
+
+	delete(self.chunks, chunk_index)
+
+	for i, x := range self.order {
+		if x == chunk_index {
+			self.order = append(self.order[:i], self.order[i+1:]...)
+			break
+		}
+	}
+}
@@ -0,0 +1,315 @@
+package goarpcsolution
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// not repeating with period of chunk size
+func testReaderData(size int) []byte {
+	ret := make([]byte, size)
+	for i := range ret {
+		ret[i] = byte(i * 7 / 3)
+	}
+	return ret
+}
+
+// reader of data, published by Ctl1 of pair. returned counter counts
+// slice requests
+func openTestBinaryReader(
+	t *testing.T,
+	pair *ARPCNodePair,
+	buffer ARPCBufferI,
+	opts *ARPCRemoteBinaryReaderOptions,
+) (*ARPCRemoteBinaryReader, *int32) {
+	t.Helper()
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	requests := countTestRequests(pair.Node0, "BufferBinaryGetSlice")
+
+	r, err := pair.Node0.BufferBinaryOpen(newTestContext(t), buffer_id, opts)
+	if err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { r.Close() })
+
+	return r, requests
+}
+
+func newTestMemBinary(t *testing.T, data []byte) *ARPCBufferMemBinary {
+	t.Helper()
+
+	ret := NewARPCBufferMemBinary("", "")
+	_, err := ret.Append(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return ret
+}
+
+func TestARPCRemoteBinaryReaderRead(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+
+	data := testReaderData(10500)
+
+	r, requests := openTestBinaryReader(
+		t,
+		pair,
+		newTestMemBinary(t, data),
+		&ARPCRemoteBinaryReaderOptions{ChunkSize: 1000, ReadAhead: 2},
+	)
+
+	if r.Size() != int64(len(data)) {
+		t.Fatalf("size %d", r.Size())
+	}
+
+	// not more than one chunk per call
+	b := make([]byte, 5000)
+	n, err := r.Read(b[:900])
+	if err != nil || n != 900 {
+		t.Fatalf("Read() returned %d, %v", n, err)
+	}
+	n, err = r.Read(b)
+	if err != nil || n != 100 {
+		t.Fatalf("Read() at chunk end returned %d, %v", n, err)
+	}
+
+	// odd reads cross chunk boundaries
+	res := append([]byte(nil), data[:1000]...)
+	for {
+		n, err := r.Read(b[:333])
+		res = append(res, b[:n]...)
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if !bytes.Equal(res, data) {
+		t.Fatal("read data differs")
+	}
+
+	// each chunk is requested once
+	if n := atomic.LoadInt32(requests); n != 11 {
+		t.Errorf("%d slice requests", n)
+	}
+
+	n, err = r.Read(b)
+	if n != 0 || err != io.EOF {
+		t.Errorf("Read() at end returned %d, %v", n, err)
+	}
+}
+
+func TestARPCRemoteBinaryReaderSeek(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+
+	data := testReaderData(2500)
+
+	r, _ := openTestBinaryReader(
+		t,
+		pair,
+		newTestMemBinary(t, data),
+		&ARPCRemoteBinaryReaderOptions{ChunkSize: 1000},
+	)
+
+	for _, i := range []struct {
+		offset   int64
+		whence   int
+		expected int64
+	}{
+		{1500, io.SeekStart, 1500},
+		{-500, io.SeekCurrent, 1000},
+		{-10, io.SeekEnd, 2490},
+	} {
+		pos, err := r.Seek(i.offset, i.whence)
+		if err != nil || pos != i.expected {
+			t.Fatalf("Seek(%d, %d) returned %d, %v",
+				i.offset, i.whence, pos, err)
+		}
+	}
+
+	b := make([]byte, 100)
+	n, err := r.Read(b)
+	if err != nil || !bytes.Equal(b[:n], data[2490:]) {
+		t.Errorf("Read() after seek returned %d, %v", n, err)
+	}
+
+	_, err = r.Seek(-1, io.SeekStart)
+	if err == nil {
+		t.Error("seek to negative position succeeded")
+	}
+
+	// position after end is allowed, reads return EOF
+	_, err = r.Seek(10, io.SeekEnd)
+	if err != nil {
+		t.Fatal(err)
+	}
+	n, err = r.Read(b)
+	if n != 0 || err != io.EOF {
+		t.Errorf("Read() after end returned %d, %v", n, err)
+	}
+}
+
+func TestARPCRemoteBinaryReaderReadAt(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+
+	data := testReaderData(2500)
+
+	r, _ := openTestBinaryReader(
+		t,
+		pair,
+		newTestMemBinary(t, data),
+		&ARPCRemoteBinaryReaderOptions{ChunkSize: 1000},
+	)
+
+	// over three chunks, up to end
+	b := make([]byte, 1600)
+	n, err := r.ReadAt(b, 999)
+	if err != io.EOF || n != 1501 || !bytes.Equal(b[:n], data[999:]) {
+		t.Fatalf("ReadAt() returned %d, %v", n, err)
+	}
+
+	n, err = r.ReadAt(b[:1000], 1000)
+	if err != nil || n != 1000 || !bytes.Equal(b[:n], data[1000:2000]) {
+		t.Errorf("ReadAt() of chunk returned %d, %v", n, err)
+	}
+
+	n, err = r.ReadAt(b, int64(len(data)))
+	if n != 0 || err != io.EOF {
+		t.Errorf("ReadAt() at end returned %d, %v", n, err)
+	}
+
+	_, err = r.ReadAt(b, -1)
+	if err == nil {
+		t.Error("ReadAt() with negative offset succeeded")
+	}
+}
+
+func TestARPCRemoteBinaryReaderEviction(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+
+	data := testReaderData(10000)
+
+	r, requests := openTestBinaryReader(
+		t,
+		pair,
+		newTestMemBinary(t, data),
+		&ARPCRemoteBinaryReaderOptions{ChunkSize: 1000, ReadAhead: 1},
+	)
+
+	_, err := io.Copy(io.Discard, r)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r.mtx.Lock()
+	cached := len(r.chunks)
+	r.mtx.Unlock()
+	if cached > 3 {
+		t.Errorf("%d chunks cached", cached)
+	}
+
+	before := atomic.LoadInt32(requests)
+
+	// first chunk is evicted and requested again
+	b := make([]byte, 10)
+	_, err = r.ReadAt(b, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(requests); n != before+1 {
+		t.Errorf("%d slice requests for evicted chunk", n-before)
+	}
+
+	// last chunk is cached
+	_, err = r.ReadAt(b, 9990)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if n := atomic.LoadInt32(requests); n != before+1 {
+		t.Errorf("cached chunk is requested again")
+	}
+}
+
+func TestARPCRemoteBinaryReaderClosed(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+
+	r, _ := openTestBinaryReader(
+		t,
+		pair,
+		newTestMemBinary(t, testReaderData(100)),
+		nil,
+	)
+
+	pair.Node0.Close()
+
+	_, err := r.Read(make([]byte, 10))
+	if !errors.Is(err, ErrARPCClosed) {
+		t.Errorf("expected closed error, got %v", err)
+	}
+}
+
+func TestARPCRemoteBinaryReaderFile(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+
+	// several items of file and chunks of reader
+	data := testReaderData(3*ARPC_BUFFER_FILE_ITEM_SIZE + 123)
+
+	name := filepath.Join(t.TempDir(), "data.bin")
+	err := os.WriteFile(name, data, 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	file, err := OpenARPCBufferFile(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	r, _ := openTestBinaryReader(
+		t,
+		pair,
+		file,
+		&ARPCRemoteBinaryReaderOptions{ReadAhead: 2},
+	)
+
+	section := io.NewSectionReader(r, 1000, 2*ARPC_BUFFER_FILE_ITEM_SIZE)
+	res, err := io.ReadAll(section)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(res, data[1000:1000+2*ARPC_BUFFER_FILE_ITEM_SIZE]) {
+		t.Error("section data differs")
+	}
+
+	req := httptest.NewRequest(http.MethodGet, "/data.bin", nil)
+	req.Header.Set("Range", "bytes=70000-150000")
+	rec := httptest.NewRecorder()
+
+	http.ServeContent(rec, req, "data.bin", time.Time{}, r)
+
+	if rec.Code != http.StatusPartialContent {
+		t.Fatalf("status %d", rec.Code)
+	}
+	if l := rec.Header().Get("Content-Length"); l != strconv.Itoa(80001) {
+		t.Errorf("Content-Length %s", l)
+	}
+	if !bytes.Equal(rec.Body.Bytes(), data[70000:150001]) {
+		t.Error("served data differs")
+	}
+}
@@ -0,0 +1,281 @@
+package goarpcsolution
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"strconv"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/AnimusPEXUS/gouuidtools"
+)
+
+// buffer of count items with values 0..count-1, published by ctl
+func publishTestItems(
+	t *testing.T,
+	ctl *ARPCNodeCtlBasic,
+	count int,
+) *gouuidtools.UUID {
+	t.Helper()
+
+	buffer := NewARPCBufferMemObject("", "")
+	for i := 0; i != count; i++ {
+		_, err := buffer.Append(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buffer_id, err := ctl.PublishBuffer(nil, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return buffer_id
+}
+
+// counts requests of method, sent by node
+func countTestRequests(node *ARPCNode, method string) *int32 {
+	ret := new(int32)
+	marker := []byte(`"` + ARPC_MSG_PREFIX_ARPC_PLUS_COLUMN + method + `"`)
+
+	push := node.PushMessageToOutsideCB
+	node.PushMessageToOutsideCB = func(data []byte) error {
+		if bytes.Contains(data, marker) {
+			atomic.AddInt32(ret, 1)
+		}
+		return push(data)
+	}
+
+	return ret
+}
+
+func checkTestItemValue(t *testing.T, item *ARPCBufferItem, expected int) {
+	t.Helper()
+
+	// values are decoded from JSON
+	if item.Value != float64(expected) {
+		t.Fatalf("item %s has value %v, expected %d",
+			item.ItemId, item.Value, expected)
+	}
+}
+
+func TestARPCRemoteBufferBatchedOrder(t *testing.T) {
+	pair := newTestNodePair(
+		t,
+		&ARPCNodePairOptions{LatencyJitter: 5 * time.Millisecond},
+	)
+
+	buffer_id := publishTestItems(t, pair.Ctl1, 100)
+
+	// requested in reverse order, so order isn't ids order in buffer
+	ids := make([]string, 0, 100)
+	for i := 99; i != -1; i-- {
+		ids = append(ids, strconv.Itoa(i))
+	}
+
+	items, err := pair.Node0.BufferGetItemsByIdsBatchedCtx(
+		newTestContext(t),
+		buffer_id,
+		ids,
+		&ARPCBufferBatchOptions{BatchSize: 7, Concurrency: 4},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != len(ids) {
+		t.Fatalf("%d items", len(items))
+	}
+
+	for i, item := range items {
+		if item.ItemId != ids[i] {
+			t.Fatalf("item #%d has id %s, expected %s", i, item.ItemId, ids[i])
+		}
+		checkTestItemValue(t, item, 99-i)
+	}
+}
+
+func TestARPCRemoteBufferBatchedFirstError(t *testing.T) {
+	pair := newTestNodePair(
+		t,
+		&ARPCNodePairOptions{Latency: 5 * time.Millisecond},
+	)
+
+	buffer_id := publishTestItems(t, pair.Ctl1, 100)
+
+	requests := countTestRequests(pair.Node0, "BufferGetItemsByIds")
+
+	// first batch fails
+	ids := []string{"missing"}
+	for i := 0; i != 100; i++ {
+		ids = append(ids, strconv.Itoa(i))
+	}
+
+	_, err := pair.Node0.BufferGetItemsByIdsBatchedCtx(
+		newTestContext(t),
+		buffer_id,
+		ids,
+		&ARPCBufferBatchOptions{BatchSize: 1, Concurrency: 2},
+	)
+	if !errors.Is(err, ARPCErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+
+	// only batches, started before first reply, are requested
+	if n := atomic.LoadInt32(requests); n > 3 {
+		t.Errorf("%d batches requested after error", n)
+	}
+
+	if n := pair.Node0.GetPendingRequestsCount(); n != 0 {
+		t.Errorf("%d pending requests after error", n)
+	}
+}
+
+func TestARPCRemoteBufferIterator(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	ctx := newTestContext(t)
+
+	// several pages of ids
+	count := 2*ARPC_BUFFER_ITEMS_IDS_MAX_COUNT + 10
+
+	buffer_id := publishTestItems(t, pair.Ctl1, count)
+
+	ids_requests := countTestRequests(pair.Node0, "BufferGetItemsIds")
+
+	first_spec := new(ARPCBufferItemSpecifier)
+	first_spec.SetIndex(5)
+
+	it := pair.Node0.BufferIterate(
+		buffer_id,
+		first_spec,
+		&ARPCBufferBatchOptions{BatchSize: 50, Concurrency: 3},
+	)
+	defer it.Close()
+
+	for i := 5; i != count; i++ {
+		item, err := it.Next(ctx)
+		if err != nil {
+			t.Fatalf("item %d: %v", i, err)
+		}
+		checkTestItemValue(t, item, i)
+	}
+
+	_, err := it.Next(ctx)
+	if err != io.EOF {
+		t.Fatalf("expected EOF, got %v", err)
+	}
+
+	// 3 pages and empty one
+	if n := atomic.LoadInt32(ids_requests); n != 4 {
+		t.Errorf("ids are requested %d times", n)
+	}
+
+	it.Close()
+
+	_, err = it.Next(ctx)
+	if err == nil {
+		t.Error("Next() after Close() succeeded")
+	}
+}
+
+func TestARPCRemoteBufferIteratorError(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	ctx := newTestContext(t)
+
+	it := pair.Node0.BufferIterate(newTestUUID(t), nil, nil)
+	defer it.Close()
+
+	_, err := it.Next(ctx)
+	if !errors.Is(err, ARPCErrNotFound) {
+		t.Fatalf("expected not found error, got %v", err)
+	}
+
+	// error is kept
+	_, err = it.Next(ctx)
+	if !errors.Is(err, ARPCErrNotFound) {
+		t.Errorf("expected not found error again, got %v", err)
+	}
+}
+
+func TestARPCRemoteBufferFollowerPages(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	ctx := newTestContext(t)
+
+	count := ARPC_BUFFER_ITEMS_IDS_MAX_COUNT + 10
+
+	buffer := NewARPCBufferMemObject("", "")
+	for i := 0; i != count; i++ {
+		_, err := buffer.Append(i)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+	buffer.SetFinished()
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(nil, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	f, err := pair.Node0.BufferFollow(ctx, buffer_id, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items := collectTestFollower(t, f, 10*time.Second)
+	if len(items) != count {
+		t.Fatalf("%d items delivered", len(items))
+	}
+	for i, value := range items {
+		if value != float64(i) {
+			t.Fatalf("item #%d has value %v", i, value)
+		}
+	}
+}
+
+// remote node returns less file items, than requested
+func TestARPCRemoteBufferBatchedFile(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+
+	count := 40
+
+	buffer_id, err := pair.Ctl1.PublishBuffer(
+		nil,
+		NewARPCBufferReaderAt(
+			testZeroReaderAt{},
+			int64(count*ARPC_BUFFER_FILE_ITEM_SIZE),
+			"",
+			"",
+		),
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	ids := make([]string, count)
+	for i := range ids {
+		ids[i] = strconv.Itoa(i)
+	}
+
+	items, err := pair.Node0.BufferGetItemsByIdsBatchedCtx(
+		newTestContext(t),
+		buffer_id,
+		ids,
+		nil,
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	if len(items) != count {
+		t.Fatalf("%d items", len(items))
+	}
+	for i, item := range items {
+		if item.ItemId != ids[i] {
+			t.Fatalf("item #%d has id %s", i, item.ItemId)
+		}
+	}
+}
@@ -0,0 +1,457 @@
+package goarpcsolution
+
+import (
+	"bytes"
+	"errors"
+	"io"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/AnimusPEXUS/gouuidtools"
+)
+
+// error messages, used by controllers to report socket states,
+// which should be restored on remote side
+const (
+	ARPC_SOCKET_ERR_MSG_EOF     = "EOF"
+	ARPC_SOCKET_ERR_MSG_TIMEOUT = "timeout"
+	ARPC_SOCKET_ERR_MSG_CLOSED  = "closed"
+)
+
+// maximum bytes count requested by single SocketRead()
+const ARPC_REMOTE_CONN_MAX_READ_SIZE = 64 * 1024
+
+// default window of push-mode connections
+const ARPC_REMOTE_CONN_STREAM_WINDOW = 256 * 1024
+
+var _ net.Conn = &ARPCRemoteConn{}
+
+type ARPCRemoteConnAddr struct {
+	ConnectedSocketId *gouuidtools.UUID
+}
+
+func (self *ARPCRemoteConnAddr) Network() string {
+	return "arpc"
+}
+
+func (self *ARPCRemoteConnAddr) String() string {
+	return self.ConnectedSocketId.Format()
+}
+
+// net.Conn implementation over remote node's connected socket.
+// all operations are translated to node's Socket* calls.
+//
+// in push mode (see ARPCNodeCtlBasic.SocketGetStreamConn) remote node
+// sends data by itself and Read() takes it from local buffer. read
+// deadline is local in this mode
+type ARPCRemoteConn struct {
+	// used for all calls, except reads. read without read deadline
+	// waits until remote socket returns something or node is closed:
+	// giving up earlier would lose data, read by remote node later
+	ResponseTimeout time.Duration
+
+	node                *ARPCNode
+	connected_socket_id *gouuidtools.UUID
+
+	mtx            *sync.Mutex
+	read_deadline  time.Time
+	write_deadline time.Time
+	closed         bool
+
+	// nil in pull mode
+	stream *xARPCRemoteConnStream
+
+	on_close func()
+}
+
+type xARPCRemoteConnStream struct {
+	window int
+
+	// uses ARPCRemoteConn.mtx
+	cond *sync.Cond
+
+	buf      bytes.Buffer
+	next_seq int
+	err      error
+
+	// consumed bytes, which aren't returned to remote node as credit yet
+	consumed int
+}
+
+func NewARPCRemoteConn(
+	node *ARPCNode,
+	connected_socket_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+) *ARPCRemoteConn {
+	self := new(ARPCRemoteConn)
+	self.node = node
+	self.connected_socket_id = connected_socket_id
+	self.ResponseTimeout = response_timeout
+	self.mtx = new(sync.Mutex)
+	return self
+}
+
+// creates connection in push mode. remote side isn't informed:
+// use ARPCNodeCtlBasic.SocketGetStreamConn() to get started connection
+func NewARPCRemoteConnStream(
+	node *ARPCNode,
+	connected_socket_id *gouuidtools.UUID,
+	response_timeout time.Duration,
+	window int,
+) *ARPCRemoteConn {
+	self := NewARPCRemoteConn(node, connected_socket_id, response_timeout)
+	self.stream = &xARPCRemoteConnStream{
+		window: window,
+		cond:   sync.NewCond(self.mtx),
+	}
+	return self
+}
+
+func (self *ARPCRemoteConn) GetConnectedSocketId() *gouuidtools.UUID {
+	return self.connected_socket_id
+}
+
+func (self *ARPCRemoteConn) IsStream() bool {
+	return self.stream != nil
+}
+
+// passes chunk, received with SocketStreamData notification
+func (self *ARPCRemoteConn) pushStreamData(
+	seq int,
+	data []byte,
+	stream_err error,
+) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	st := self.stream
+
+	if self.closed || st.err != nil {
+		return
+	}
+
+	defer st.cond.Broadcast()
+
+	if seq != st.next_seq {
+		st.err = errors.New("socket stream chunk lost or reordered")
+		return
+	}
+
+	// remote node may send only as much, as it got credit for: window
+	// minus buffered bytes minus consumed bytes, not returned as credit
+	if st.buf.Len()+st.consumed+len(data) > st.window {
+		st.err = errors.New("socket stream window exceeded")
+		return
+	}
+
+	st.next_seq++
+	st.buf.Write(data)
+
+	if stream_err != nil {
+		st.err = self.remoteError(false, false, stream_err, nil)
+	}
+}
+
+// ends stream with err, after already buffered data is read
+func (self *ARPCRemoteConn) pushStreamEnd(err error) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	st := self.stream
+
+	if st.err == nil {
+		st.err = err
+	}
+
+	st.cond.Broadcast()
+}
+
+// translates remote error message to local socket error
+func (self *ARPCRemoteConn) remoteError(
+	timedout bool,
+	closed bool,
+	result_err error,
+	err error,
+) error {
+	switch {
+	case errors.Is(result_err, ARPCErrEOF):
+		return io.EOF
+	case errors.Is(result_err, ARPCErrTimeout):
+		return os.ErrDeadlineExceeded
+	case errors.Is(result_err, ARPCErrClosed):
+		return net.ErrClosed
+	}
+
+	if timedout {
+		return os.ErrDeadlineExceeded
+	}
+
+	if closed {
+		return net.ErrClosed
+	}
+
+	return remoteResultToError(timedout, closed, result_err, err)
+}
+
+// true if connection or it's node is closed
+func (self *ARPCRemoteConn) isClosed() bool {
+	self.mtx.Lock()
+	closed := self.closed
+	self.mtx.Unlock()
+	return closed || self.node.IsClosed()
+}
+
+func (self *ARPCRemoteConn) Read(b []byte) (n int, err error) {
+	if self.isClosed() {
+		return 0, net.ErrClosed
+	}
+
+	if len(b) == 0 {
+		return 0, nil
+	}
+
+	if self.stream != nil {
+		return self.readStream(b)
+	}
+
+	size := len(b)
+	if size > ARPC_REMOTE_CONN_MAX_READ_SIZE {
+		size = ARPC_REMOTE_CONN_MAX_READ_SIZE
+	}
+
+	// no timeout, unless read deadline is set
+	self.mtx.Lock()
+	var timeout time.Duration
+	if !self.read_deadline.IsZero() {
+		timeout = time.Until(self.read_deadline) + self.ResponseTimeout
+	}
+	self.mtx.Unlock()
+
+	data, timedout, closed, result_err, err :=
+		self.node.SocketRead(self.connected_socket_id, size, timeout)
+	err = self.remoteError(timedout, closed, result_err, err)
+	if err != nil {
+		return 0, err
+	}
+
+	if len(data) > len(b) {
+		return 0, errors.New("remote returned more data than requested")
+	}
+
+	return copy(b, data), nil
+}
+
+func (self *ARPCRemoteConn) readStream(b []byte) (n int, err error) {
+	st := self.stream
+
+	self.mtx.Lock()
+
+	var timer *time.Timer
+	var timer_deadline time.Time
+
+	for st.buf.Len() == 0 && st.err == nil && !self.closed {
+		if !self.read_deadline.IsZero() {
+			if !time.Now().Before(self.read_deadline) {
+				self.mtx.Unlock()
+				if timer != nil {
+					timer.Stop()
+				}
+				return 0, os.ErrDeadlineExceeded
+			}
+
+			if timer == nil || !timer_deadline.Equal(self.read_deadline) {
+				if timer != nil {
+					timer.Stop()
+				}
+				timer_deadline = self.read_deadline
+				timer = time.AfterFunc(
+					time.Until(timer_deadline),
+					func() {
+						self.mtx.Lock()
+						st.cond.Broadcast()
+						self.mtx.Unlock()
+					},
+				)
+			}
+		}
+
+		st.cond.Wait()
+	}
+
+	if timer != nil {
+		timer.Stop()
+	}
+
+	if self.closed {
+		self.mtx.Unlock()
+		return 0, net.ErrClosed
+	}
+
+	if st.buf.Len() == 0 {
+		err = st.err
+		self.mtx.Unlock()
+		return 0, err
+	}
+
+	n, _ = st.buf.Read(b)
+
+	// credit is returned by halves of window, not to send notification
+	// on each read
+	credit := 0
+	st.consumed += n
+	if st.consumed >= st.window/2 {
+		credit = st.consumed
+		st.consumed = 0
+	}
+
+	self.mtx.Unlock()
+
+	if credit != 0 {
+		self.node.SocketStreamCredit(self.connected_socket_id, credit)
+	}
+
+	return n, nil
+}
+
+func (self *ARPCRemoteConn) Write(b []byte) (n int, err error) {
+	for n != len(b) {
+		if self.isClosed() {
+			return n, net.ErrClosed
+		}
+
+		chunk := b[n:]
+		if len(chunk) > ARPC_REMOTE_CONN_MAX_READ_SIZE {
+			chunk = chunk[:ARPC_REMOTE_CONN_MAX_READ_SIZE]
+		}
+
+		self.mtx.Lock()
+		timeout := self.ResponseTimeout
+		if !self.write_deadline.IsZero() {
+			timeout = time.Until(self.write_deadline) + self.ResponseTimeout
+		}
+		self.mtx.Unlock()
+
+		written, timedout, closed, result_err, err :=
+			self.node.SocketWrite(self.connected_socket_id, chunk, timeout)
+		err = self.remoteError(timedout, closed, result_err, err)
+		n += written
+		if err != nil {
+			return n, err
+		}
+		if written == 0 {
+			return n, io.ErrShortWrite
+		}
+	}
+	return n, nil
+}
+
+func (self *ARPCRemoteConn) Close() error {
+	self.mtx.Lock()
+	if self.closed {
+		self.mtx.Unlock()
+		return nil
+	}
+	self.closed = true
+	if self.stream != nil {
+		self.stream.cond.Broadcast()
+	}
+	self.mtx.Unlock()
+
+	if self.on_close != nil {
+		self.on_close()
+	}
+
+	if self.node.IsClosed() {
+		return nil
+	}
+
+	timedout, closed, result_err, err :=
+		self.node.SocketClose(self.connected_socket_id, self.ResponseTimeout)
+	return self.remoteError(timedout, closed, result_err, err)
+}
+
+func (self *ARPCRemoteConn) LocalAddr() net.Addr {
+	return &ARPCRemoteConnAddr{ConnectedSocketId: self.connected_socket_id}
+}
+
+func (self *ARPCRemoteConn) RemoteAddr() net.Addr {
+	return &ARPCRemoteConnAddr{ConnectedSocketId: self.connected_socket_id}
+}
+
+func (self *ARPCRemoteConn) SetDeadline(t time.Time) error {
+	if self.isClosed() {
+		return net.ErrClosed
+	}
+
+	self.mtx.Lock()
+	self.read_deadline = t
+	self.write_deadline = t
+	if self.stream != nil {
+		self.stream.cond.Broadcast()
+	}
+	self.mtx.Unlock()
+
+	if self.stream != nil {
+		// remote socket is read by remote node on it's own
+		timedout, closed, result_err, err :=
+			self.node.SocketSetWriteDeadline(
+				self.connected_socket_id,
+				t,
+				self.ResponseTimeout,
+			)
+		return self.remoteError(timedout, closed, result_err, err)
+	}
+
+	timedout, closed, result_err, err :=
+		self.node.SocketSetDeadline(
+			self.connected_socket_id,
+			t,
+			self.ResponseTimeout,
+		)
+	return self.remoteError(timedout, closed, result_err, err)
+}
+
+func (self *ARPCRemoteConn) SetReadDeadline(t time.Time) error {
+	if self.isClosed() {
+		return net.ErrClosed
+	}
+
+	self.mtx.Lock()
+	self.read_deadline = t
+	if self.stream != nil {
+		self.stream.cond.Broadcast()
+	}
+	self.mtx.Unlock()
+
+	if self.stream != nil {
+		return nil
+	}
+
+	timedout, closed, result_err, err :=
+		self.node.SocketSetReadDeadline(
+			self.connected_socket_id,
+			t,
+			self.ResponseTimeout,
+		)
+	return self.remoteError(timedout, closed, result_err, err)
+}
+
+func (self *ARPCRemoteConn) SetWriteDeadline(t time.Time) error {
+	if self.isClosed() {
+		return net.ErrClosed
+	}
+
+	self.mtx.Lock()
+	self.write_deadline = t
+	self.mtx.Unlock()
+
+	timedout, closed, result_err, err :=
+		self.node.SocketSetWriteDeadline(
+			self.connected_socket_id,
+			t,
+			self.ResponseTimeout,
+		)
+	return self.remoteError(timedout, closed, result_err, err)
+}
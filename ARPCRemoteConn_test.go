@@ -0,0 +1,140 @@
+package goarpcsolution
+
+import (
+	"bytes"
+	"errors"
+	"net"
+	"testing"
+	"time"
+)
+
+func newTestStreamConn(t *testing.T, window int) *ARPCRemoteConn {
+	t.Helper()
+
+	ctl, _ := newTestCtl(t)
+
+	node := NewARPCNode(ctl)
+	node.PushMessageToOutsideCB = func(data []byte) error { return nil }
+
+	return NewARPCRemoteConnStream(node, newTestUUID(t), time.Second, window)
+}
+
+func TestARPCRemoteConnStreamWindow(t *testing.T) {
+	conn := newTestStreamConn(t, 10)
+
+	conn.pushStreamData(0, make([]byte, 6), nil)
+	conn.pushStreamData(1, make([]byte, 4), nil)
+
+	// 2 bytes are consumed, but not returned as credit yet
+	n, err := conn.Read(make([]byte, 2))
+	if err != nil || n != 2 {
+		t.Fatalf("Read() returned %d, %v", n, err)
+	}
+
+	conn.pushStreamData(2, make([]byte, 1), nil)
+
+	n, err = conn.Read(make([]byte, 10))
+	if err != nil || n != 8 {
+		t.Fatalf("Read() returned %d, %v", n, err)
+	}
+
+	_, err = conn.Read(make([]byte, 10))
+	if err == nil {
+		t.Fatal("stream isn't failed after window is exceeded")
+	}
+}
+
+func TestARPCRemoteConnStreamWindowCredit(t *testing.T) {
+	conn := newTestStreamConn(t, 10)
+
+	conn.pushStreamData(0, make([]byte, 10), nil)
+
+	// consumed bytes are returned as credit
+	n, err := conn.Read(make([]byte, 10))
+	if err != nil || n != 10 {
+		t.Fatalf("Read() returned %d, %v", n, err)
+	}
+
+	conn.pushStreamData(1, make([]byte, 10), nil)
+
+	n, err = conn.Read(make([]byte, 10))
+	if err != nil || n != 10 {
+		t.Fatalf("Read() returned %d, %v", n, err)
+	}
+}
+
+// Read without read deadline isn't limited by call timeouts
+func TestARPCRemoteConnReadWithoutDeadline(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+
+	ls_id, _, remotes := listenTestPipe(t, pair.Ctl1)
+
+	conn, err := pair.Ctl0.SocketDial(ls_id)
+	if err != nil {
+		t.Fatal(err)
+	}
+	conn.(*ARPCRemoteConn).ResponseTimeout = 10 * time.Millisecond
+
+	remote := <-remotes
+
+	go func() {
+		time.Sleep(200 * time.Millisecond)
+		remote.Write([]byte("late"))
+	}()
+
+	b := make([]byte, 10)
+	n, err := conn.Read(b)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !bytes.Equal(b[:n], []byte("late")) {
+		t.Errorf("Read() returned %q", b[:n])
+	}
+}
+
+func TestARPCRemoteConnNodeClosedUnderRead(t *testing.T) {
+	for _, stream := range []bool{false, true} {
+		pair := newTestNodePair(t, nil)
+
+		ls_id, _, _ := listenTestPipe(t, pair.Ctl1)
+
+		var conn net.Conn
+		var err error
+		if stream {
+			conn, err = pair.Ctl0.SocketDialStream(ls_id, 0)
+		} else {
+			conn, err = pair.Ctl0.SocketDial(ls_id)
+		}
+		if err != nil {
+			t.Fatal(err)
+		}
+
+		res := make(chan error, 1)
+		go func() {
+			_, err := conn.Read(make([]byte, 10))
+			res <- err
+		}()
+
+		time.Sleep(50 * time.Millisecond)
+		pair.Node0.Close()
+
+		select {
+		case err := <-res:
+			if !errors.Is(err, net.ErrClosed) {
+				t.Errorf("stream %v: Read() returned %v", stream, err)
+			}
+		case <-time.After(5 * time.Second):
+			t.Fatalf("stream %v: Read() isn't finished by Close()", stream)
+		}
+
+		_, err = conn.Write([]byte("x"))
+		if !errors.Is(err, net.ErrClosed) {
+			t.Errorf("stream %v: Write() returned %v", stream, err)
+		}
+
+		err = conn.SetDeadline(time.Now())
+		if !errors.Is(err, net.ErrClosed) {
+			t.Errorf("stream %v: SetDeadline() returned %v", stream, err)
+		}
+	}
+}
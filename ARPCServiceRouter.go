@@ -0,0 +1,366 @@
+package goarpcsolution
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"reflect"
+	"runtime/debug"
+	"sync"
+
+	"github.com/AnimusPEXUS/gouuidtools"
+)
+
+var (
+	type_error     = reflect.TypeOf((*error)(nil)).Elem()
+	type_arpc_call = reflect.TypeOf((*ARPCCall)(nil))
+	type_arpc_arg  = reflect.TypeOf((*ARPCCallArg)(nil))
+	type_uuid      = reflect.TypeOf((*gouuidtools.UUID)(nil))
+)
+
+// routes incomming calls to registered Go functions by call Name.
+//
+// function parameters are filled from call args:
+//   - optional first parameter of type *ARPCCall receives the call itself;
+//   - positional args are passed to following parameters in order;
+//   - named args (if any) are decoded into next parameter, which must be
+//     struct, pointer to struct or map (mapstructure tags are respected);
+//   - parameters left without args receive zero values.
+//
+// basic values are decoded with mapstructure. buffers, transmissions and
+// sockets can be received as *ARPCCallArg, as respective
+// *ARPCCallArgValueType* or as *gouuidtools.UUID.
+//
+// function may return any number of values. last one may be error.
+// other values are sent as positional args of reply. ARPCBufferI,
+// ARPCTransmissionI, ARPCListeningSocketI, ARPCConnectedSocketI,
+// *ARPCCallArgValueType* and *ARPCCallArg values are sent as respective
+// args. error is sent as reply error code, message and data
+// (see ReplyWithError)
+type ARPCServiceRouter struct {
+	ctl *ARPCNodeCtlBasic
+
+	mtx   sync.Mutex
+	funcs map[string]*xARPCServiceFunc
+
+	// called for calls with names which aren't registered. if nil -
+	// reply with ARPCErrorCodeUnsupported is sent
+	OnUnknownCallCB func(call *ARPCCall) (error, error)
+
+	// called if registered function panics. remote side gets only
+	// ARPCErrorCodeInternal reply without panic details. if nil - panic
+	// is written to standard logger
+	OnPanicCB func(call *ARPCCall, value any, stack []byte)
+}
+
+type xARPCServiceFunc struct {
+	fn        reflect.Value
+	with_call bool
+	has_err   bool
+}
+
+func NewARPCServiceRouter(ctl *ARPCNodeCtlBasic) *ARPCServiceRouter {
+	self := new(ARPCServiceRouter)
+	self.ctl = ctl
+	self.funcs = make(map[string]*xARPCServiceFunc)
+	return self
+}
+
+// sets ctl.OnCallCB to self.HandleCall
+func (self *ARPCServiceRouter) Attach() {
+	self.ctl.OnCallCB = self.HandleCall
+}
+
+// register function under name. replaces previously registered one
+func (self *ARPCServiceRouter) RegisterFunc(name string, fn any) error {
+	if name == "" {
+		return errors.New("empty name")
+	}
+
+	f, err := newARPCServiceFunc(reflect.ValueOf(fn))
+	if err != nil {
+		return err
+	}
+
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	self.funcs[name] = f
+
+	return nil
+}
+
+// registers all exported methods of service as prefix+"."+MethodName
+// (or just MethodName if prefix is empty)
+func (self *ARPCServiceRouter) RegisterService(
+	prefix string,
+	service any,
+) error {
+	v := reflect.ValueOf(service)
+	if !v.IsValid() {
+		return errors.New("service is nil")
+	}
+
+	t := v.Type()
+
+	funcs := make(map[string]*xARPCServiceFunc)
+
+	for i := 0; i != t.NumMethod(); i++ {
+		m := t.Method(i)
+		if !m.IsExported() {
+			continue
+		}
+
+		f, err := newARPCServiceFunc(v.Method(i))
+		if err != nil {
+			return fmt.Errorf("method %s: %w", m.Name, err)
+		}
+
+		name := m.Name
+		if prefix != "" {
+			name = prefix + "." + name
+		}
+
+		funcs[name] = f
+	}
+
+	if len(funcs) == 0 {
+		return errors.New("service have no exported methods")
+	}
+
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	for k, v := range funcs {
+		self.funcs[k] = v
+	}
+
+	return nil
+}
+
+func (self *ARPCServiceRouter) Unregister(name string) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	delete(self.funcs, name)
+}
+
+func (self *ARPCServiceRouter) IsRegistered(name string) bool {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+	_, ok := self.funcs[name]
+	return ok
+}
+
+// suitable for ARPCNodeCtlBasic.OnCallCB
+func (self *ARPCServiceRouter) HandleCall(call *ARPCCall) (error, error) {
+	self.mtx.Lock()
+	f, ok := self.funcs[call.Name]
+	self.mtx.Unlock()
+
+	if !ok {
+		if self.OnUnknownCallCB != nil {
+			return self.OnUnknownCallCB(call)
+		}
+		return nil, self.ctl.ReplyWithError(
+			call.CallId,
+			ARPCErrorf(ARPCErrorCodeUnsupported, "unknown call: %s", call.Name),
+		)
+	}
+
+	reply_args, reply_err := f.call(call, self.reportPanic)
+
+	return nil, self.ctl.ReplyWithError(call.CallId, reply_err, reply_args...)
+}
+
+func (self *ARPCServiceRouter) reportPanic(
+	call *ARPCCall,
+	value any,
+	stack []byte,
+) {
+	if self.OnPanicCB != nil {
+		self.OnPanicCB(call, value, stack)
+		return
+	}
+	log.Printf("arpc: call %s panicked: %v\n%s", call.Name, value, stack)
+}
+
+func newARPCServiceFunc(fn reflect.Value) (*xARPCServiceFunc, error) {
+	if !fn.IsValid() || fn.Kind() != reflect.Func || fn.IsNil() {
+		return nil, errors.New("not a function")
+	}
+
+	t := fn.Type()
+
+	if t.IsVariadic() {
+		return nil, errors.New("variadic functions are not supported")
+	}
+
+	ret := &xARPCServiceFunc{fn: fn}
+
+	ret.with_call = t.NumIn() != 0 && t.In(0) == type_arpc_call
+
+	ret.has_err = t.NumOut() != 0 && t.Out(t.NumOut()-1) == type_error
+
+	return ret, nil
+}
+
+func (self *xARPCServiceFunc) call(
+	call *ARPCCall,
+	on_panic func(call *ARPCCall, value any, stack []byte),
+) (reply_args []*ARPCCallArg, reply_err error) {
+
+	defer func() {
+		if x := recover(); x != nil {
+			on_panic(call, x, debug.Stack())
+			reply_args = nil
+			reply_err = NewARPCError(
+				ARPCErrorCodeInternal,
+				"internal error",
+				nil,
+			)
+		}
+	}()
+
+	t := self.fn.Type()
+
+	in := make([]reflect.Value, t.NumIn())
+	for i := range in {
+		in[i] = reflect.Zero(t.In(i))
+	}
+
+	param := 0
+	if self.with_call {
+		in[0] = reflect.ValueOf(call)
+		param++
+	}
+
+	named := make(map[string]any)
+
+	for i, arg := range call.Args {
+		if arg.Name != "" {
+			if arg.Basic != nil {
+				named[arg.Name] = arg.Basic.Value
+			} else {
+				named[arg.Name] = arg
+			}
+			continue
+		}
+
+		if param == len(in) {
+			return nil, ARPCErrorf(
+				ARPCErrorCodeInvalidArgument,
+				"too many positional args: %d",
+				i+1,
+			)
+		}
+
+		v, err := serviceArgValue(arg, t.In(param))
+		if err != nil {
+			return nil, ARPCErrorf(
+				ARPCErrorCodeInvalidArgument,
+				"arg #%d: %s", i, err,
+			)
+		}
+		in[param] = v
+		param++
+	}
+
+	if len(named) != 0 {
+		if param == len(in) {
+			return nil, NewARPCError(
+				ARPCErrorCodeInvalidArgument,
+				"named args are not accepted",
+				nil,
+			)
+		}
+
+		p := reflect.New(t.In(param))
+		err := mapstructureDecode(named, p.Interface(), serviceArgDecodeHook)
+		if err != nil {
+			return nil, ARPCErrorf(
+				ARPCErrorCodeInvalidArgument,
+				"named args: %s", err,
+			)
+		}
+		in[param] = p.Elem()
+	}
+
+	out := self.fn.Call(in)
+
+	if self.has_err {
+		last := out[len(out)-1]
+		out = out[:len(out)-1]
+		if !last.IsNil() {
+			return nil, last.Interface().(error)
+		}
+	}
+
+	for _, i := range out {
+		reply_args = append(reply_args, NewARPCCallArgFromValue(i.Interface()))
+	}
+
+	return reply_args, nil
+}
+
+// decodes named non-basic args, which are placed into map as *ARPCCallArg
+func serviceArgDecodeHook(
+	from reflect.Type,
+	to reflect.Type,
+	data any,
+) (any, error) {
+	if from != type_arpc_arg {
+		return data, nil
+	}
+
+	v, err := serviceArgValue(data.(*ARPCCallArg), to)
+	if err != nil {
+		return nil, err
+	}
+
+	return v.Interface(), nil
+}
+
+func serviceArgValue(arg *ARPCCallArg, t reflect.Type) (reflect.Value, error) {
+	if t == type_arpc_arg {
+		return reflect.ValueOf(arg), nil
+	}
+
+	if arg.Basic != nil {
+		p := reflect.New(t)
+		err := mapstructureDecode(arg.Basic.Value, p.Interface())
+		if err != nil {
+			return reflect.Value{}, err
+		}
+		return p.Elem(), nil
+	}
+
+	var (
+		value any
+		id    *gouuidtools.UUID
+		what  string
+	)
+
+	switch {
+	case arg.Buffer != nil:
+		value, id, what = arg.Buffer, arg.Buffer.Id, "buffer"
+	case arg.Transmission != nil:
+		value, id, what = arg.Transmission, arg.Transmission.Id, "transmission"
+	case arg.ListeningSocket != nil:
+		value, id, what =
+			arg.ListeningSocket, arg.ListeningSocket.Id, "listening socket"
+	case arg.ConnectedSocket != nil:
+		value, id, what =
+			arg.ConnectedSocket, arg.ConnectedSocket.Id, "connected socket"
+	default:
+		return reflect.Value{}, errors.New("invalid arg")
+	}
+
+	switch {
+	case t == type_uuid:
+		return reflect.ValueOf(id), nil
+	case reflect.TypeOf(value).AssignableTo(t):
+		return reflect.ValueOf(value), nil
+	}
+
+	return reflect.Value{}, fmt.Errorf("%s can't be passed as %s", what, t)
+}
@@ -0,0 +1,200 @@
+package goarpcsolution
+
+import (
+	"errors"
+	"strings"
+	"testing"
+)
+
+type testServiceNamedArgs struct {
+	Text  string `mapstructure:"text"`
+	Count int    `mapstructure:"count"`
+}
+
+// router, attached to Ctl1 of pair. panics are sent to returned channel
+func newTestServiceRouter(
+	t *testing.T,
+	pair *ARPCNodePair,
+) (*ARPCServiceRouter, chan any) {
+	t.Helper()
+
+	panics := make(chan any, 1)
+
+	router := NewARPCServiceRouter(pair.Ctl1)
+	router.OnPanicCB = func(call *ARPCCall, value any, stack []byte) {
+		panics <- value
+	}
+	router.Attach()
+
+	for name, fn := range map[string]any{
+		"add": func(a, b int) int { return a + b },
+		"name": func(call *ARPCCall, suffix string) string {
+			return call.Name + suffix
+		},
+		"repeat": func(args testServiceNamedArgs) string {
+			return strings.Repeat(args.Text, args.Count)
+		},
+		"split": func(s string) (string, string, error) {
+			a, b, ok := strings.Cut(s, " ")
+			if !ok {
+				return "", "", ARPCErrorf(
+					ARPCErrorCodeNotFound,
+					"no space in %q", s,
+				)
+			}
+			return a, b, nil
+		},
+		"fail":  func() error { return errors.New("plain error") },
+		"panic": func() { panic("secret details") },
+	} {
+		err := router.RegisterFunc(name, fn)
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	return router, panics
+}
+
+// ARPCError, replied by remote side
+func testReplyError(t *testing.T, err error) *ARPCError {
+	t.Helper()
+
+	if !errors.Is(err, ErrARPCRemote) {
+		t.Fatalf("expected remote error, got %v", err)
+	}
+
+	var ret *ARPCError
+	if !errors.As(err, &ret) {
+		t.Fatalf("%v isn't ARPCError", err)
+	}
+
+	return ret
+}
+
+func TestARPCServiceRouterArgs(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	newTestServiceRouter(t, pair)
+	ctx := newTestContext(t)
+
+	sum, err := CallTyped[int](ctx, pair.Ctl0, "add", []any{2, 3})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != 5 {
+		t.Errorf("add returned %d", sum)
+	}
+
+	// missing positional args get zero values
+	sum, err = CallTyped[int](ctx, pair.Ctl0, "add", []any{2})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if sum != 2 {
+		t.Errorf("add with one arg returned %d", sum)
+	}
+
+	name, err := CallTyped[string](ctx, pair.Ctl0, "name", "!")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if name != "name!" {
+		t.Errorf("name returned %q", name)
+	}
+
+	text, err := CallTyped[string](
+		ctx,
+		pair.Ctl0,
+		"repeat",
+		&testServiceNamedArgs{Text: "ab", Count: 3},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if text != "ababab" {
+		t.Errorf("repeat returned %q", text)
+	}
+
+	parts, err := CallTyped[[]string](ctx, pair.Ctl0, "split", "a b")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(parts) != 2 || parts[0] != "a" || parts[1] != "b" {
+		t.Errorf("split returned %v", parts)
+	}
+}
+
+func TestARPCServiceRouterInvalidArgs(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	newTestServiceRouter(t, pair)
+	ctx := newTestContext(t)
+
+	for _, i := range []struct {
+		name string
+		args any
+	}{
+		{"add", []any{"x", 1}},
+		{"add", []any{1, 2, 3}},
+		{"repeat", map[string]any{"count": "many"}},
+		{"add", map[string]any{"a": 1}},
+	} {
+		_, err := CallTyped[int](ctx, pair.Ctl0, i.name, i.args)
+		e := testReplyError(t, err)
+		if e.Code != ARPCErrorCodeInvalidArgument {
+			t.Errorf("%s %v: reply error %v", i.name, i.args, e)
+		}
+	}
+
+	_, err := pair.Ctl0.CallAndWait(ctx, "unknown")
+	if e := testReplyError(t, err); e.Code != ARPCErrorCodeUnsupported {
+		t.Errorf("unknown call: reply error %v", e)
+	}
+}
+
+func TestARPCServiceRouterErrors(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	newTestServiceRouter(t, pair)
+	ctx := newTestContext(t)
+
+	_, err := CallTyped[[]string](ctx, pair.Ctl0, "split", "ab")
+	e := testReplyError(t, err)
+	if e.Code != ARPCErrorCodeNotFound || e.Message != `no space in "ab"` {
+		t.Errorf("split: reply error %v", e)
+	}
+
+	err = CallTypedNoResult(ctx, pair.Ctl0, "fail", nil)
+	e = testReplyError(t, err)
+	if e.Code != ARPCErrorCodeGeneric || e.Message != "plain error" {
+		t.Errorf("fail: reply error %v", e)
+	}
+}
+
+func TestARPCServiceRouterPanic(t *testing.T) {
+	pair := newTestNodePair(t, nil)
+	_, panics := newTestServiceRouter(t, pair)
+	ctx := newTestContext(t)
+
+	err := CallTypedNoResult(ctx, pair.Ctl0, "panic", nil)
+	e := testReplyError(t, err)
+	if e.Code != ARPCErrorCodeInternal {
+		t.Errorf("reply error %v", e)
+	}
+	if strings.Contains(e.Message, "secret") {
+		t.Errorf("panic details are sent to remote side: %q", e.Message)
+	}
+
+	select {
+	case value := <-panics:
+		if value != "secret details" {
+			t.Errorf("OnPanicCB got %v", value)
+		}
+	default:
+		t.Error("panic isn't reported locally")
+	}
+
+	// router keeps working after panic
+	sum, err := CallTyped[int](ctx, pair.Ctl0, "add", []any{1, 1})
+	if err != nil || sum != 2 {
+		t.Errorf("add after panic: %d, %v", sum, err)
+	}
+}
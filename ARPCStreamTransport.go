@@ -0,0 +1,310 @@
+package goarpcsolution
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"sync"
+)
+
+type ARPCStreamFraming uint8
+
+const (
+	// each message is preceded by 4 byte big endian length
+	ARPCStreamFramingLengthPrefixed ARPCStreamFraming = iota
+
+	// each message is single line of JSON, terminated with '\n'.
+	// can be used only with text codecs (see ARPCCodecI.IsText())
+	ARPCStreamFramingNewline
+)
+
+// default limit for single incomming message size
+const ARPC_STREAM_TRANSPORT_MAX_MESSAGE_SIZE = 16 * 1024 * 1024
+
+var ErrARPCStreamTransportClosed = errors.New("stream transport closed")
+
+var ErrARPCStreamMessageTooLarge = errors.New("stream message too large")
+
+// runs ARPCNode over io.ReadWriteCloser (net.Conn, os.Pipe, exec.Cmd stdio).
+// usage: create, set callbacks, call Start().
+//
+// note: incomming messages are passed to node in reading goroutine one by
+// one, so node handlers shouldn't wait for responses from remote side,
+// otherwise reading stalls. (ARPCNode handlers which may wait, already
+// work in separate goroutines)
+type ARPCStreamTransport struct {
+
+	// results of node.PushMessageFromOutside(), if any of them is not nil.
+	// such errors doesn't stop the transport.
+	//   error #0 - if protocol error
+	//   error #1 - other errors
+	OnMessageErrorsCB func(error, error)
+
+	// called once, if stream reading or writing failed.
+	// stream end (io.EOF) isn't reported here
+	OnStreamErrorCB func(error)
+
+	// called once after stream and node are closed
+	OnClosedCB func()
+
+	// incomming messages larger than this, are treated as stream error.
+	// set before Start()
+	MaxMessageSize int
+
+	node    *ARPCNode
+	stream  io.ReadWriteCloser
+	framing ARPCStreamFraming
+
+	write_mtx sync.Mutex
+
+	close_mtx sync.Mutex
+	closed    bool
+
+	started bool
+	done    chan struct{}
+}
+
+// sets node's PushMessageToOutsideCB to write into stream
+func NewARPCStreamTransport(
+	node *ARPCNode,
+	stream io.ReadWriteCloser,
+	framing ARPCStreamFraming,
+) *ARPCStreamTransport {
+	self := new(ARPCStreamTransport)
+	self.node = node
+	self.stream = stream
+	self.framing = framing
+	self.MaxMessageSize = ARPC_STREAM_TRANSPORT_MAX_MESSAGE_SIZE
+	self.done = make(chan struct{})
+
+	self.node.PushMessageToOutsideCB = self.writeMessage
+
+	return self
+}
+
+func (self *ARPCStreamTransport) GetNode() *ARPCNode {
+	return self.node
+}
+
+// starts reading goroutine. may be called only once
+func (self *ARPCStreamTransport) Start() error {
+	self.close_mtx.Lock()
+	defer self.close_mtx.Unlock()
+
+	if self.closed {
+		return ErrARPCStreamTransportClosed
+	}
+
+	if self.started {
+		return errors.New("already started")
+	}
+
+	switch self.framing {
+	case ARPCStreamFramingLengthPrefixed:
+	case ARPCStreamFramingNewline:
+		if !self.node.GetCodec().IsText() {
+			return fmt.Errorf(
+				"newline framing can't be used with %s codec",
+				self.node.GetCodec().Name(),
+			)
+		}
+	default:
+		return fmt.Errorf("unsupported framing: %d", self.framing)
+	}
+
+	self.started = true
+
+	go self.readLoop()
+
+	return nil
+}
+
+// closed after transport closed
+func (self *ARPCStreamTransport) Done() <-chan struct{} {
+	return self.done
+}
+
+// waits until transport closed
+func (self *ARPCStreamTransport) Wait() {
+	<-self.done
+}
+
+func (self *ARPCStreamTransport) IsClosed() bool {
+	self.close_mtx.Lock()
+	defer self.close_mtx.Unlock()
+	return self.closed
+}
+
+// closes stream and node
+func (self *ARPCStreamTransport) Close() {
+	self.closeWithError(nil)
+}
+
+func (self *ARPCStreamTransport) closeWithError(err error) {
+	self.close_mtx.Lock()
+	if self.closed {
+		self.close_mtx.Unlock()
+		return
+	}
+	self.closed = true
+	self.close_mtx.Unlock()
+
+	if err != nil && self.OnStreamErrorCB != nil {
+		self.OnStreamErrorCB(err)
+	}
+
+	self.stream.Close()
+
+	if !self.node.IsClosed() {
+		self.node.Close()
+	}
+
+	close(self.done)
+
+	if self.OnClosedCB != nil {
+		self.OnClosedCB()
+	}
+}
+
+func (self *ARPCStreamTransport) writeMessage(data []byte) error {
+	if self.IsClosed() {
+		return ErrARPCStreamTransportClosed
+	}
+
+	var frame []byte
+
+	switch self.framing {
+	case ARPCStreamFramingLengthPrefixed:
+		frame = make([]byte, 4, 4+len(data))
+		binary.BigEndian.PutUint32(frame, uint32(len(data)))
+		frame = append(frame, data...)
+	case ARPCStreamFramingNewline:
+		if bytes.IndexByte(data, '\n') != -1 {
+			// newlines can be only insignificant whitespace in valid JSON
+			b := new(bytes.Buffer)
+			err := json.Compact(b, data)
+			if err != nil {
+				return err
+			}
+			data = b.Bytes()
+		}
+		frame = make([]byte, 0, len(data)+1)
+		frame = append(frame, data...)
+		frame = append(frame, '\n')
+	default:
+		return fmt.Errorf("unsupported framing: %d", self.framing)
+	}
+
+	self.write_mtx.Lock()
+	_, err := self.stream.Write(frame)
+	self.write_mtx.Unlock()
+
+	if err != nil {
+		go self.closeWithError(err)
+		return err
+	}
+
+	return nil
+}
+
+func (self *ARPCStreamTransport) readLoop() {
+	var err error
+
+	switch self.framing {
+	case ARPCStreamFramingLengthPrefixed:
+		err = self.readLoopLengthPrefixed()
+	case ARPCStreamFramingNewline:
+		err = self.readLoopNewline()
+	}
+
+	if errors.Is(err, io.EOF) {
+		err = nil
+	}
+
+	self.closeWithError(err)
+}
+
+func (self *ARPCStreamTransport) readLoopLengthPrefixed() error {
+	r := bufio.NewReader(self.stream)
+	header := make([]byte, 4)
+
+	for {
+		_, err := io.ReadFull(r, header)
+		if err != nil {
+			return err
+		}
+
+		size := binary.BigEndian.Uint32(header)
+		if uint64(size) > uint64(self.MaxMessageSize) {
+			return ErrARPCStreamMessageTooLarge
+		}
+
+		data := make([]byte, size)
+		_, err = io.ReadFull(r, data)
+		if err != nil {
+			if errors.Is(err, io.EOF) {
+				err = io.ErrUnexpectedEOF
+			}
+			return err
+		}
+
+		if !self.pushMessage(data) {
+			return nil
+		}
+	}
+}
+
+func (self *ARPCStreamTransport) readLoopNewline() error {
+	// scanner's limit is the larger of it's max and initial buffer size,
+	// so buffer mustn't be larger than limit
+	size := self.MaxMessageSize + 1
+	buf_size := size
+	if buf_size > 64*1024 {
+		buf_size = 64 * 1024
+	}
+
+	s := bufio.NewScanner(self.stream)
+	s.Buffer(make([]byte, 0, buf_size), size)
+
+	for s.Scan() {
+		data := bytes.TrimSpace(s.Bytes())
+		if len(data) == 0 {
+			continue
+		}
+
+		// scanner reuses it's buffer
+		data = append([]byte(nil), data...)
+
+		if !self.pushMessage(data) {
+			return nil
+		}
+	}
+
+	err := s.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		err = ErrARPCStreamMessageTooLarge
+	}
+	return err
+}
+
+// false if reading should be stopped
+func (self *ARPCStreamTransport) pushMessage(data []byte) bool {
+	if self.IsClosed() {
+		return false
+	}
+
+	err_proto, err := self.node.PushMessageFromOutside(data)
+	if errors.Is(err, ErrARPCClosed) {
+		return false
+	}
+
+	if (err_proto != nil || err != nil) && self.OnMessageErrorsCB != nil {
+		self.OnMessageErrorsCB(err_proto, err)
+	}
+
+	return true
+}
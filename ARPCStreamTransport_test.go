@@ -0,0 +1,179 @@
+package goarpcsolution
+
+import (
+	"errors"
+	"io"
+	"net"
+	"testing"
+	"time"
+)
+
+// returns at most one byte per Read(), so each message is split
+// across reads
+type testOneByteReader struct {
+	io.ReadWriteCloser
+}
+
+func (self testOneByteReader) Read(b []byte) (int, error) {
+	if len(b) > 1 {
+		b = b[:1]
+	}
+	return self.ReadWriteCloser.Read(b)
+}
+
+// node with transport over one end of pipe. stream errors are sent to
+// returned channel
+func newTestStreamTransport(
+	t *testing.T,
+	stream io.ReadWriteCloser,
+	framing ARPCStreamFraming,
+) (*ARPCStreamTransport, *ARPCNodeCtlBasic, chan error) {
+	t.Helper()
+
+	ctl := NewARPCNodeCtlBasic()
+	ctl.SetDebug(false)
+	ctl.ResponseTimeout = 5 * time.Second
+
+	node := NewARPCNode(ctl)
+	node.CtxResponseTimeout = 5 * time.Second
+
+	stream_errors := make(chan error, 1)
+
+	tr := NewARPCStreamTransport(node, stream, framing)
+	tr.OnStreamErrorCB = func(err error) { stream_errors <- err }
+	t.Cleanup(tr.Close)
+
+	return tr, ctl, stream_errors
+}
+
+func TestARPCStreamTransportRoundTrip(t *testing.T) {
+	for _, i := range []struct {
+		name    string
+		framing ARPCStreamFraming
+		split   bool
+	}{
+		{"length prefixed", ARPCStreamFramingLengthPrefixed, false},
+		{"length prefixed split", ARPCStreamFramingLengthPrefixed, true},
+		{"newline", ARPCStreamFramingNewline, false},
+		{"newline split", ARPCStreamFramingNewline, true},
+	} {
+		t.Run(
+			i.name,
+			func(t *testing.T) {
+				testARPCStreamTransportRoundTrip(t, i.framing, i.split)
+			},
+		)
+	}
+}
+
+func testARPCStreamTransportRoundTrip(
+	t *testing.T,
+	framing ARPCStreamFraming,
+	split bool,
+) {
+	var stream0, stream1 io.ReadWriteCloser
+	stream0, stream1 = net.Pipe()
+	if split {
+		stream0 = testOneByteReader{stream0}
+		stream1 = testOneByteReader{stream1}
+	}
+
+	tr0, _, _ := newTestStreamTransport(t, stream0, framing)
+	tr1, ctl1, _ := newTestStreamTransport(t, stream1, framing)
+
+	for _, tr := range []*ARPCStreamTransport{tr0, tr1} {
+		err := tr.Start()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	buffer := NewARPCBufferMemObject("", "")
+	_, err := buffer.Append("line 1\nline 2")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	buffer_id, err := ctl1.PublishBuffer(nil, buffer)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	items, err := tr0.GetNode().BufferGetItemsByIdsCtx(
+		newTestContext(t),
+		buffer_id,
+		[]string{"0"},
+	)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if len(items) != 1 || items[0].Value != "line 1\nline 2" {
+		t.Errorf("BufferGetItemsByIds() returned %v", items)
+	}
+
+	tr0.Close()
+
+	select {
+	case <-tr1.Done():
+	case <-time.After(5 * time.Second):
+		t.Fatal("remote transport isn't closed after stream end")
+	}
+}
+
+func TestARPCStreamTransportMessageTooLarge(t *testing.T) {
+	for _, i := range []struct {
+		name    string
+		framing ARPCStreamFraming
+	}{
+		{"length prefixed", ARPCStreamFramingLengthPrefixed},
+		{"newline", ARPCStreamFramingNewline},
+	} {
+		t.Run(
+			i.name,
+			func(t *testing.T) {
+				testARPCStreamTransportMessageTooLarge(t, i.framing)
+			},
+		)
+	}
+}
+
+func testARPCStreamTransportMessageTooLarge(
+	t *testing.T,
+	framing ARPCStreamFraming,
+) {
+	stream0, stream1 := net.Pipe()
+
+	tr0, _, _ := newTestStreamTransport(t, stream0, framing)
+	tr1, _, stream_errors := newTestStreamTransport(t, stream1, framing)
+	tr1.MaxMessageSize = 64
+
+	for _, tr := range []*ARPCStreamTransport{tr0, tr1} {
+		err := tr.Start()
+		if err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	// request fails on write or is closed with node, depending on how
+	// much of it remote side read
+	_, err := tr0.GetNode().BufferGetInfoCtx(
+		newTestContext(t),
+		newTestUUID(t),
+	)
+	if err == nil {
+		t.Error("request succeeded")
+	}
+
+	select {
+	case err := <-stream_errors:
+		if !errors.Is(err, ErrARPCStreamMessageTooLarge) {
+			t.Errorf("expected too large error, got %v", err)
+		}
+	case <-time.After(5 * time.Second):
+		t.Fatal("stream error isn't reported")
+	}
+
+	if !tr1.IsClosed() || !tr1.GetNode().IsClosed() {
+		t.Error("transport isn't closed after stream error")
+	}
+}
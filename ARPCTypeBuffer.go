@@ -24,6 +24,8 @@ const (
 )
 
 type ARPCBufferInfo struct {
+	// buffers don't know their ids (same buffer may be published under
+	// several ids): controller sets Id from buffer's record
 	Id               *gouuidtools.UUID
 	HumanTitle       string
 	HumanDescription string
@@ -49,6 +51,227 @@ type ARPCBufferI interface {
 	GetItem(id string) (*ARPCBufferItem, bool, error)
 }
 
+// optional interface for buffers, which can access items by their index
+type ARPCBufferIndexableI interface {
+	ARPCBufferI
+	// if not found - it's not error and 2nd result is false
+	GetItemByIndex(index int) (*ARPCBufferItem, bool, error)
+}
+
+// buffers in ARPCBufferModeBinary mode must implement this
+type ARPCBufferBinaryI interface {
+	ARPCBufferI
+	// size in bytes
+	BinarySize() (int, error)
+	// returns bytes from start_index to end_index (not including)
+	BinarySlice(start_index, end_index int) ([]byte, error)
+}
+
+// optional interface for buffers, which can get item's id and time
+// cheaper, than whole item. used, where values aren't needed (item ids
+// and times, resolving of item specifiers). returned items have nil
+// Value
+type ARPCBufferItemMetaI interface {
+	ARPCBufferI
+	// if not found - it's not error and 2nd result is false
+	GetItemMeta(id string) (*ARPCBufferItem, bool, error)
+	// if not found - it's not error and 2nd result is false
+	GetItemMetaByIndex(index int) (*ARPCBufferItem, bool, error)
+}
+
+// optional interface for buffers, which can inform remote nodes about
+// own updates. node's BufferUpdated() should be called for each
+// subscribed node on buffer change
+type ARPCBufferUpdatesNotifierI interface {
+	ARPCBufferI
+	SubscribeNodeOnUpdates(node *ARPCNode, buffer_id *gouuidtools.UUID)
+	UnsubscribeNodeFromUpdates(node *ARPCNode, buffer_id *gouuidtools.UUID)
+}
+
+// optional interface for buffers and transmissions, which can inform
+// controller, that they are finished. f is called once, when object
+// becomes finished (never, if it's already finished). returned function
+// unsubscribes f
+type ARPCFinishNotifierI interface {
+	OnFinished(f func()) (unsubscribe func())
+}
+
+// callbacks of ARPCFinishNotifierI. not thread-safe: owner's mutex must
+// be locked
+type xARPCFinishCallbacks struct {
+	next int
+	cbs  map[int]func()
+}
+
+func (self *xARPCFinishCallbacks) add(f func()) int {
+	if self.cbs == nil {
+		self.cbs = make(map[int]func())
+	}
+	self.next++
+	self.cbs[self.next] = f
+	return self.next
+}
+
+func (self *xARPCFinishCallbacks) remove(id int) {
+	delete(self.cbs, id)
+}
+
+// returns all callbacks and removes them
+func (self *xARPCFinishCallbacks) take() []func() {
+	ret := make([]func(), 0, len(self.cbs))
+	for _, f := range self.cbs {
+		ret = append(ret, f)
+	}
+	self.cbs = nil
+	return ret
+}
+
+// uses GetItemByIndex() if buffer is ARPCBufferIndexableI. else item ids are
+// assumed to be item indexes
+func bufferGetItemByIndex(
+	buffer ARPCBufferI,
+	index int,
+) (*ARPCBufferItem, bool, error) {
+	if x, ok := buffer.(ARPCBufferIndexableI); ok {
+		return x.GetItemByIndex(index)
+	}
+	return buffer.GetItem(strconv.Itoa(index))
+}
+
+// item without value, if buffer is ARPCBufferItemMetaI. else whole item
+func bufferGetItemMeta(
+	buffer ARPCBufferI,
+	id string,
+) (*ARPCBufferItem, bool, error) {
+	if x, ok := buffer.(ARPCBufferItemMetaI); ok {
+		return x.GetItemMeta(id)
+	}
+	return buffer.GetItem(id)
+}
+
+// item without value, if buffer is ARPCBufferItemMetaI. else whole item
+// (see bufferGetItemByIndex())
+func bufferGetItemMetaByIndex(
+	buffer ARPCBufferI,
+	index int,
+) (*ARPCBufferItem, bool, error) {
+	if x, ok := buffer.(ARPCBufferItemMetaI); ok {
+		return x.GetItemMetaByIndex(index)
+	}
+	return bufferGetItemByIndex(buffer, index)
+}
+
+// returns index of item, pointed by spec. if is_last is true, spec is
+// treated as end of range, else - as start of range.
+// for index specifiers, negative values are counted from the end of
+// buffer. results of index and time specifiers are clamped, so result
+// may be -1 or ItemCount() if there are no items in range.
+func bufferResolveItemSpecifier(
+	buffer ARPCBufferI,
+	spec *ARPCBufferItemSpecifier,
+	is_last bool,
+) (
+	index int,
+	err_processing_not_internal, err_processing_internal error,
+) {
+
+	count := buffer.ItemCount()
+
+	var ok bool
+
+	t, _ := spec.Type()
+
+	switch t {
+	default:
+		return 0,
+			NewARPCError(ARPCErrorCodeInvalidArgument, "invalid specifier", nil),
+			nil
+
+	case ARPCBufferItemSpecifierTypeIndex:
+		index, ok = spec.Index()
+		if !ok {
+			return 0,
+				NewARPCError(
+					ARPCErrorCodeInvalidArgument,
+					"invalid index specifier",
+					nil,
+				),
+				nil
+		}
+
+		if index < 0 {
+			index = count + index
+		}
+
+		if index < 0 {
+			index = -1
+			if !is_last {
+				index = 0
+			}
+		}
+
+		if index >= count {
+			index = count
+			if is_last {
+				index = count - 1
+			}
+		}
+
+		return index, nil, nil
+
+	case ARPCBufferItemSpecifierTypeTime:
+		tm, ok := spec.Time()
+		if !ok {
+			return 0,
+				NewARPCError(
+					ARPCErrorCodeInvalidArgument,
+					"invalid time specifier",
+					nil,
+				),
+				nil
+		}
+
+		if !is_last {
+			for i := 0; i != count; i++ {
+				item, found, err := bufferGetItemMetaByIndex(buffer, i)
+				if err != nil {
+					return 0, nil, err
+				}
+				if found && !item.ItemTime.Before(tm) {
+					return i, nil, nil
+				}
+			}
+			return count, nil, nil
+		}
+
+		for i := count - 1; i != -1; i-- {
+			item, found, err := bufferGetItemMetaByIndex(buffer, i)
+			if err != nil {
+				return 0, nil, err
+			}
+			if found && !item.ItemTime.After(tm) {
+				return i, nil, nil
+			}
+		}
+		return -1, nil, nil
+
+	case ARPCBufferItemSpecifierTypeString:
+		id, _ := spec.StringVal()
+
+		for i := 0; i != count; i++ {
+			item, found, err := bufferGetItemMetaByIndex(buffer, i)
+			if err != nil {
+				return 0, nil, err
+			}
+			if found && item.ItemId == id {
+				return i, nil, nil
+			}
+		}
+
+		return 0, NewARPCError(ARPCErrorCodeNotFound, "item not found", nil), nil
+	}
+}
+
 type ARPCBufferItemSpecifierType uint8
 
 const (
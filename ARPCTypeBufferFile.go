@@ -0,0 +1,420 @@
+package goarpcsolution
+
+import (
+	"errors"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+var _ ARPCBufferIndexableI = &ARPCBufferFile{}
+var _ ARPCBufferBinaryI = &ARPCBufferFile{}
+var _ ARPCBufferItemMetaI = &ARPCBufferFile{}
+var _ ARPCFinishNotifierI = &ARPCBufferFile{}
+
+var _ ARPCBufferIndexableI = &ARPCBufferDir{}
+
+// size of items of ARPCBufferFile
+const ARPC_BUFFER_FILE_ITEM_SIZE = 64 * 1024
+
+// buffer in ARPCBufferModeBinary mode, which serves data of file (or of
+// any io.ReaderAt) on demand, without loading it into memory.
+//
+// items are consecutive ARPC_BUFFER_FILE_ITEM_SIZE parts of data (last
+// may be shorter), item ids are item indexes. slices are read into memory
+// as whole (controller limits their size, see
+// ARPC_BUFFER_BINARY_MAX_SLICE_SIZE). item ids and times are served
+// without reading data (see ARPCBufferItemMetaI).
+// if source implements io.Closer, it's closed on Close() (controller
+// calls it, when buffer's record is deleted)
+type ARPCBufferFile struct {
+	mtx         sync.Mutex
+	info        ARPCBufferInfo
+	on_finished xARPCFinishCallbacks
+
+	r io.ReaderAt
+
+	// current size and modification time of data
+	stat func() (int64, time.Time, error)
+
+	closer io.Closer
+}
+
+// size and modification time are taken from f.Stat() on each request,
+// so growing file is served with it's current size. buffer isn't
+// finished: call SetFinished(), when file won't change anymore
+func NewARPCBufferFile(
+	f *os.File,
+	human_title string,
+	human_description string,
+) *ARPCBufferFile {
+	self := newARPCBufferFile(f, human_title, human_description)
+	self.stat = func() (int64, time.Time, error) {
+		st, err := f.Stat()
+		if err != nil {
+			return 0, time.Time{}, err
+		}
+		return st.Size(), st.ModTime(), nil
+	}
+	return self
+}
+
+// opens file for reading. file's base name becomes HumanTitle.
+// file must not change: buffer is finished
+func OpenARPCBufferFile(
+	name string,
+	human_description string,
+) (*ARPCBufferFile, error) {
+	f, err := os.Open(name)
+	if err != nil {
+		return nil, err
+	}
+
+	st, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return nil, err
+	}
+
+	if st.IsDir() {
+		f.Close()
+		return nil, errors.New("is a directory")
+	}
+
+	ret := NewARPCBufferFile(f, filepath.Base(name), human_description)
+	ret.info.Finished = true
+	return ret, nil
+}
+
+// size of r's data must not change: buffer is finished. item times are
+// time of creation
+func NewARPCBufferReaderAt(
+	r io.ReaderAt,
+	size int64,
+	human_title string,
+	human_description string,
+) *ARPCBufferFile {
+	self := newARPCBufferFile(r, human_title, human_description)
+	self.info.Finished = true
+	t := time.Now()
+	self.stat = func() (int64, time.Time, error) {
+		return size, t, nil
+	}
+	return self
+}
+
+func newARPCBufferFile(
+	r io.ReaderAt,
+	human_title string,
+	human_description string,
+) *ARPCBufferFile {
+	self := new(ARPCBufferFile)
+	self.info.Mode = ARPCBufferModeBinary
+	self.info.HumanTitle = human_title
+	self.info.HumanDescription = human_description
+	self.r = r
+	if c, ok := r.(io.Closer); ok {
+		self.closer = c
+	}
+	return self
+}
+
+func (self *ARPCBufferFile) GetInfo() *ARPCBufferInfo {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	ret := self.info
+	return &ret
+}
+
+func (self *ARPCBufferFile) IsFinished() bool {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	return self.info.Finished
+}
+
+// marks buffer as finished: data won't change anymore
+func (self *ARPCBufferFile) SetFinished() {
+	self.mtx.Lock()
+	if self.info.Finished {
+		self.mtx.Unlock()
+		return
+	}
+	self.info.Finished = true
+	on_finished := self.on_finished.take()
+	self.mtx.Unlock()
+
+	for _, f := range on_finished {
+		f()
+	}
+}
+
+func (self *ARPCBufferFile) OnFinished(f func()) func() {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.info.Finished {
+		return func() {}
+	}
+
+	id := self.on_finished.add(f)
+
+	return func() {
+		self.mtx.Lock()
+		defer self.mtx.Unlock()
+		self.on_finished.remove(id)
+	}
+}
+
+func (self *ARPCBufferFile) ItemCount() int {
+	size, _, err := self.stat()
+	if err != nil {
+		return 0
+	}
+	return int((size + ARPC_BUFFER_FILE_ITEM_SIZE - 1) / ARPC_BUFFER_FILE_ITEM_SIZE)
+}
+
+func (self *ARPCBufferFile) GetItem(id string) (*ARPCBufferItem, bool, error) {
+	index, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, false, nil
+	}
+	return self.GetItemByIndex(index)
+}
+
+func (self *ARPCBufferFile) GetItemByIndex(
+	index int,
+) (*ARPCBufferItem, bool, error) {
+	item, start, end, err := self.itemMeta(index)
+	if item == nil || err != nil {
+		return nil, false, err
+	}
+
+	item.Value, err = self.readAt(start, end)
+	if err != nil {
+		return nil, false, err
+	}
+
+	return item, true, nil
+}
+
+func (self *ARPCBufferFile) GetItemMeta(id string) (*ARPCBufferItem, bool, error) {
+	index, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, false, nil
+	}
+	return self.GetItemMetaByIndex(index)
+}
+
+// item without data. file isn't read
+func (self *ARPCBufferFile) GetItemMetaByIndex(
+	index int,
+) (*ARPCBufferItem, bool, error) {
+	item, _, _, err := self.itemMeta(index)
+	if item == nil || err != nil {
+		return nil, false, err
+	}
+	return item, true, nil
+}
+
+// item without value and it's data range. nil if no such item
+func (self *ARPCBufferFile) itemMeta(
+	index int,
+) (item *ARPCBufferItem, start, end int64, err error) {
+	size, mod_time, err := self.stat()
+	if err != nil {
+		return nil, 0, 0, err
+	}
+
+	start = int64(index) * ARPC_BUFFER_FILE_ITEM_SIZE
+	if index < 0 || start >= size {
+		return nil, 0, 0, nil
+	}
+
+	end = start + ARPC_BUFFER_FILE_ITEM_SIZE
+	if end > size {
+		end = size
+	}
+
+	item = &ARPCBufferItem{
+		ItemId:   strconv.Itoa(index),
+		ItemTime: mod_time,
+	}
+
+	return item, start, end, nil
+}
+
+func (self *ARPCBufferFile) BinarySize() (int, error) {
+	size, _, err := self.stat()
+	if err != nil {
+		return 0, err
+	}
+	return int(size), nil
+}
+
+func (self *ARPCBufferFile) BinarySlice(
+	start_index, end_index int,
+) ([]byte, error) {
+	size, _, err := self.stat()
+	if err != nil {
+		return nil, err
+	}
+
+	if start_index < 0 ||
+		end_index < start_index ||
+		int64(end_index) > size {
+		return nil, errors.New("invalid start_index/end_index values")
+	}
+
+	return self.readAt(int64(start_index), int64(end_index))
+}
+
+func (self *ARPCBufferFile) readAt(start, end int64) ([]byte, error) {
+	ret := make([]byte, end-start)
+
+	n, err := self.r.ReadAt(ret, start)
+	if n == len(ret) {
+		// io.ReaderAt may return io.EOF with last bytes
+		return ret, nil
+	}
+
+	if err == nil || err == io.EOF {
+		// data was truncated
+		err = io.ErrUnexpectedEOF
+	}
+
+	return nil, err
+}
+
+func (self *ARPCBufferFile) Close() error {
+	if self.closer == nil {
+		return nil
+	}
+	return self.closer.Close()
+}
+
+// value of ARPCBufferDir items
+type ARPCBufferDirEntry struct {
+	// relative to listed directory, with '/' separators
+	Path    string
+	Name    string
+	IsDir   bool
+	Size    int64
+	ModTime time.Time
+}
+
+// buffer in ARPCBufferModeObject mode, which lists directory tree.
+// listing is made once, on creation; buffer is finished.
+// item values are *ARPCBufferDirEntry, ordered by path (directories go
+// before their contents). item ids are item indexes, item times are time
+// of listing
+type ARPCBufferDir struct {
+	info  ARPCBufferInfo
+	items []*ARPCBufferItem
+}
+
+// if recursive is false, only entries of root itself are listed.
+// entries which can't be accessed are skipped. root's base name becomes
+// HumanTitle
+func NewARPCBufferDir(
+	root string,
+	recursive bool,
+	human_description string,
+) (*ARPCBufferDir, error) {
+	self := new(ARPCBufferDir)
+	self.info.Mode = ARPCBufferModeObject
+	self.info.HumanTitle = filepath.Base(root)
+	self.info.HumanDescription = human_description
+	self.info.Finished = true
+
+	t := time.Now()
+
+	err := filepath.WalkDir(
+		root,
+		func(path string, d fs.DirEntry, err error) error {
+			if path == root {
+				// error for root itself is returned to caller
+				return err
+			}
+
+			if err != nil {
+				if d != nil && d.IsDir() {
+					return fs.SkipDir
+				}
+				return nil
+			}
+
+			rel, err := filepath.Rel(root, path)
+			if err != nil {
+				return err
+			}
+
+			fi, err := d.Info()
+			if err != nil {
+				return nil
+			}
+
+			entry := &ARPCBufferDirEntry{
+				Path:    filepath.ToSlash(rel),
+				Name:    d.Name(),
+				IsDir:   d.IsDir(),
+				ModTime: fi.ModTime(),
+			}
+
+			if !d.IsDir() {
+				entry.Size = fi.Size()
+			}
+
+			self.items = append(
+				self.items,
+				&ARPCBufferItem{
+					ItemId:   strconv.Itoa(len(self.items)),
+					ItemTime: t,
+					Value:    entry,
+				},
+			)
+
+			if d.IsDir() && !recursive {
+				return fs.SkipDir
+			}
+
+			return nil
+		},
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	return self, nil
+}
+
+func (self *ARPCBufferDir) GetInfo() *ARPCBufferInfo {
+	ret := self.info
+	return &ret
+}
+
+func (self *ARPCBufferDir) ItemCount() int {
+	return len(self.items)
+}
+
+func (self *ARPCBufferDir) GetItem(id string) (*ARPCBufferItem, bool, error) {
+	index, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, false, nil
+	}
+	return self.GetItemByIndex(index)
+}
+
+func (self *ARPCBufferDir) GetItemByIndex(
+	index int,
+) (*ARPCBufferItem, bool, error) {
+	if index < 0 || index >= len(self.items) {
+		return nil, false, nil
+	}
+	return self.items[index], true, nil
+}
@@ -0,0 +1,63 @@
+package goarpcsolution
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestARPCBufferFileFinished(t *testing.T) {
+	name := filepath.Join(t.TempDir(), "data.bin")
+	err := os.WriteFile(name, []byte("data"), 0o600)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	opened, err := OpenARPCBufferFile(name, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer opened.Close()
+
+	if !opened.GetInfo().Finished {
+		t.Error("opened file isn't finished")
+	}
+
+	if !NewARPCBufferReaderAt(testZeroReaderAt{}, 10, "", "").GetInfo().Finished {
+		t.Error("reader isn't finished")
+	}
+
+	f, err := os.OpenFile(name, os.O_RDWR, 0)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// growing file
+	buffer := NewARPCBufferFile(f, "", "")
+	defer buffer.Close()
+
+	if buffer.GetInfo().Finished {
+		t.Error("file is finished")
+	}
+
+	finished := 0
+	buffer.OnFinished(func() { finished++ })
+
+	_, err = f.WriteAt([]byte("more"), 4)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	size, err := buffer.BinarySize()
+	if err != nil || size != 8 {
+		t.Errorf("BinarySize() returned %d, %v", size, err)
+	}
+
+	buffer.SetFinished()
+	buffer.SetFinished()
+
+	if !buffer.GetInfo().Finished || finished != 1 {
+		t.Errorf("finished %v, callback called %d times",
+			buffer.GetInfo().Finished, finished)
+	}
+}
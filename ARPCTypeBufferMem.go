@@ -0,0 +1,296 @@
+package goarpcsolution
+
+import (
+	"errors"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/AnimusPEXUS/gouuidtools"
+)
+
+var _ ARPCBufferIndexableI = &ARPCBufferMemObject{}
+var _ ARPCBufferUpdatesNotifierI = &ARPCBufferMemObject{}
+var _ ARPCFinishNotifierI = &ARPCBufferMemObject{}
+
+var _ ARPCBufferIndexableI = &ARPCBufferMemBinary{}
+var _ ARPCBufferBinaryI = &ARPCBufferMemBinary{}
+var _ ARPCBufferUpdatesNotifierI = &ARPCBufferMemBinary{}
+var _ ARPCFinishNotifierI = &ARPCBufferMemBinary{}
+
+// common part of in-memory buffers.
+// item ids are item indexes, item times are times of append
+type xARPCBufferMem struct {
+	mtx *sync.Mutex
+
+	info  ARPCBufferInfo
+	items []*ARPCBufferItem
+
+	subscribers []*xARPCBufferMemSubscriber
+
+	on_finished xARPCFinishCallbacks
+}
+
+type xARPCBufferMemSubscriber struct {
+	node      *ARPCNode
+	buffer_id *gouuidtools.UUID
+}
+
+func (self *xARPCBufferMem) init(
+	mode ARPCBufferMode,
+	human_title string,
+	human_description string,
+) {
+	self.mtx = new(sync.Mutex)
+	self.info.Mode = mode
+	self.info.HumanTitle = human_title
+	self.info.HumanDescription = human_description
+}
+
+// returned Id is nil. see ARPCBufferInfo.Id
+func (self *xARPCBufferMem) GetInfo() *ARPCBufferInfo {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	ret := self.info
+	return &ret
+}
+
+func (self *xARPCBufferMem) ItemCount() int {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	return len(self.items)
+}
+
+func (self *xARPCBufferMem) GetItem(id string) (*ARPCBufferItem, bool, error) {
+	index, err := strconv.Atoi(id)
+	if err != nil {
+		return nil, false, nil
+	}
+	return self.GetItemByIndex(index)
+}
+
+func (self *xARPCBufferMem) GetItemByIndex(
+	index int,
+) (*ARPCBufferItem, bool, error) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if index < 0 || index >= len(self.items) {
+		return nil, false, nil
+	}
+
+	return self.items[index], true, nil
+}
+
+func (self *xARPCBufferMem) IsFinished() bool {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	return self.info.Finished
+}
+
+// marks buffer as finished. no items can be appended after this
+func (self *xARPCBufferMem) SetFinished() {
+	self.mtx.Lock()
+	if self.info.Finished {
+		self.mtx.Unlock()
+		return
+	}
+	self.info.Finished = true
+	on_finished := self.on_finished.take()
+	self.mtx.Unlock()
+
+	self.notifySubscribers()
+
+	for _, f := range on_finished {
+		f()
+	}
+}
+
+func (self *xARPCBufferMem) OnFinished(f func()) func() {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.info.Finished {
+		return func() {}
+	}
+
+	id := self.on_finished.add(f)
+
+	return func() {
+		self.mtx.Lock()
+		defer self.mtx.Unlock()
+		self.on_finished.remove(id)
+	}
+}
+
+// subscribers are not notified: caller should call notifySubscribers()
+func (self *xARPCBufferMem) appendValue(value any) (*ARPCBufferItem, error) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.info.Finished {
+		return nil, errors.New("buffer is finished")
+	}
+
+	t := time.Now()
+
+	// item times should not go backwards
+	if l := len(self.items); l != 0 && t.Before(self.items[l-1].ItemTime) {
+		t = self.items[l-1].ItemTime
+	}
+
+	item := &ARPCBufferItem{
+		BufferId: self.info.Id,
+		ItemId:   strconv.Itoa(len(self.items)),
+		ItemTime: t,
+		Value:    value,
+	}
+
+	self.items = append(self.items, item)
+
+	return item, nil
+}
+
+func (self *xARPCBufferMem) SubscribeNodeOnUpdates(
+	node *ARPCNode,
+	buffer_id *gouuidtools.UUID,
+) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	for _, i := range self.subscribers {
+		if i.node == node && uuidsEqual(i.buffer_id, buffer_id) {
+			return
+		}
+	}
+
+	self.subscribers = append(
+		self.subscribers,
+		&xARPCBufferMemSubscriber{
+			node:      node,
+			buffer_id: buffer_id,
+		},
+	)
+}
+
+func (self *xARPCBufferMem) UnsubscribeNodeFromUpdates(
+	node *ARPCNode,
+	buffer_id *gouuidtools.UUID,
+) {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	for i := len(self.subscribers) - 1; i != -1; i-- {
+		x := self.subscribers[i]
+		if x.node == node && uuidsEqual(x.buffer_id, buffer_id) {
+			self.subscribers = append(
+				self.subscribers[:i],
+				self.subscribers[i+1:]...,
+			)
+		}
+	}
+}
+
+// subscribers with closed nodes and subscribers which can't be
+// notified - are removed
+func (self *xARPCBufferMem) notifySubscribers() {
+	self.mtx.Lock()
+	subscribers := append([]*xARPCBufferMemSubscriber{}, self.subscribers...)
+	self.mtx.Unlock()
+
+	for _, i := range subscribers {
+		if i.node.IsClosed() || i.node.BufferUpdated(i.buffer_id) != nil {
+			self.UnsubscribeNodeFromUpdates(i.node, i.buffer_id)
+		}
+	}
+}
+
+// thread-safe appendable in-memory buffer in ARPCBufferModeObject mode
+type ARPCBufferMemObject struct {
+	xARPCBufferMem
+}
+
+func NewARPCBufferMemObject(
+	human_title string,
+	human_description string,
+) *ARPCBufferMemObject {
+	self := new(ARPCBufferMemObject)
+	self.init(ARPCBufferModeObject, human_title, human_description)
+	return self
+}
+
+// error if buffer is finished
+func (self *ARPCBufferMemObject) Append(value any) (*ARPCBufferItem, error) {
+	item, err := self.appendValue(value)
+	if err != nil {
+		return nil, err
+	}
+
+	self.notifySubscribers()
+
+	return item, nil
+}
+
+// thread-safe appendable in-memory buffer in ARPCBufferModeBinary mode.
+// each Append() creates new item, but BinarySize() and BinarySlice() work
+// with concatenation of all items
+type ARPCBufferMemBinary struct {
+	xARPCBufferMem
+
+	data_mtx *sync.Mutex
+	data     []byte
+}
+
+func NewARPCBufferMemBinary(
+	human_title string,
+	human_description string,
+) *ARPCBufferMemBinary {
+	self := new(ARPCBufferMemBinary)
+	self.init(ARPCBufferModeBinary, human_title, human_description)
+	self.data_mtx = new(sync.Mutex)
+	return self
+}
+
+// data is copied. error if buffer is finished
+func (self *ARPCBufferMemBinary) Append(data []byte) (*ARPCBufferItem, error) {
+	data = append([]byte{}, data...)
+
+	self.data_mtx.Lock()
+
+	item, err := self.appendValue(data)
+	if err != nil {
+		self.data_mtx.Unlock()
+		return nil, err
+	}
+
+	self.data = append(self.data, data...)
+
+	self.data_mtx.Unlock()
+
+	self.notifySubscribers()
+
+	return item, nil
+}
+
+func (self *ARPCBufferMemBinary) BinarySize() (int, error) {
+	self.data_mtx.Lock()
+	defer self.data_mtx.Unlock()
+
+	return len(self.data), nil
+}
+
+func (self *ARPCBufferMemBinary) BinarySlice(
+	start_index, end_index int,
+) ([]byte, error) {
+	self.data_mtx.Lock()
+	defer self.data_mtx.Unlock()
+
+	if start_index < 0 || end_index < start_index || end_index > len(self.data) {
+		return nil, errors.New("invalid start_index/end_index values")
+	}
+
+	return append([]byte{}, self.data[start_index:end_index]...), nil
+}
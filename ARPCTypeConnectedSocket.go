@@ -1,3 +1,16 @@
 package goarpcsolution
 
-type ARPCConnectedSocketI interface{}
+import (
+	"time"
+)
+
+// mimics Go's net.Conn interface, so any net.Conn can be used as
+// connected socket
+type ARPCConnectedSocketI interface {
+	Read(b []byte) (n int, err error)
+	Write(b []byte) (n int, err error)
+	Close() error
+	SetDeadline(t time.Time) error
+	SetReadDeadline(t time.Time) error
+	SetWriteDeadline(t time.Time) error
+}
@@ -1,3 +1,88 @@
 package goarpcsolution
 
-type ARPCListeningSocketI interface{}
+import (
+	"errors"
+	"net"
+	"sync"
+)
+
+// listening socket creates new connected sockets then remote node
+// calls SocketOpen()
+type ARPCListeningSocketI interface {
+	Open() (ARPCConnectedSocketI, error)
+	Close() error
+}
+
+var _ ARPCListeningSocketI = &ARPCListeningSocketListener{}
+var _ ARPCListeningSocketI = &ARPCListeningSocketDialer{}
+
+// each Open() returns next connection accepted by Listener
+type ARPCListeningSocketListener struct {
+	Listener net.Listener
+}
+
+func NewARPCListeningSocketListener(
+	listener net.Listener,
+) *ARPCListeningSocketListener {
+	self := new(ARPCListeningSocketListener)
+	self.Listener = listener
+	return self
+}
+
+func (self *ARPCListeningSocketListener) Open() (ARPCConnectedSocketI, error) {
+	return self.Listener.Accept()
+}
+
+func (self *ARPCListeningSocketListener) Close() error {
+	return self.Listener.Close()
+}
+
+// each Open() dials new connection using Dial. this is the way to forward
+// remote node's connections to local service
+type ARPCListeningSocketDialer struct {
+	Dial func() (net.Conn, error)
+
+	closed_mtx *sync.Mutex
+	closed     bool
+}
+
+func NewARPCListeningSocketDialer(
+	dial func() (net.Conn, error),
+) *ARPCListeningSocketDialer {
+	self := new(ARPCListeningSocketDialer)
+	self.Dial = dial
+	self.closed_mtx = new(sync.Mutex)
+	return self
+}
+
+// dials network address on each Open()
+func NewARPCListeningSocketDialerNetwork(
+	network, address string,
+) *ARPCListeningSocketDialer {
+	return NewARPCListeningSocketDialer(
+		func() (net.Conn, error) {
+			return net.Dial(network, address)
+		},
+	)
+}
+
+func (self *ARPCListeningSocketDialer) Open() (ARPCConnectedSocketI, error) {
+	self.closed_mtx.Lock()
+	closed := self.closed
+	self.closed_mtx.Unlock()
+
+	if closed {
+		return nil, errors.New("closed")
+	}
+
+	return self.Dial()
+}
+
+// already opened connections are not closed
+func (self *ARPCListeningSocketDialer) Close() error {
+	self.closed_mtx.Lock()
+	defer self.closed_mtx.Unlock()
+
+	self.closed = true
+	return nil
+}
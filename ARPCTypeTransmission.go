@@ -1,13 +1,181 @@
 package goarpcsolution
 
 import (
+	"errors"
+	"sync"
+
 	"github.com/AnimusPEXUS/gouuidtools"
 )
 
+type ARPCTransmissionState uint8
+
+const (
+	ARPCTransmissionStateInvalid ARPCTransmissionState = iota
+
+	// buffers may still be added and updated
+	ARPCTransmissionStateOpen
+
+	// all buffers are complete
+	ARPCTransmissionStateFinished
+
+	// transmission interrupted. buffers may be incomplete
+	ARPCTransmissionStateAborted
+)
+
 type ARPCTransmissionInfo struct {
+	Id               *gouuidtools.UUID
 	HumanTitle       string
 	HumanDescription string
-	BufferIds        []*gouuidtools.UUID
+	State            ARPCTransmissionState
+
+	// BufferNames[i] is name of buffer with BufferIds[i]
+	BufferIds   []*gouuidtools.UUID
+	BufferNames []string
+}
+
+// Id used to provide predefined Id and/or to
+// return resulting Id, generated and/or used by controller
+type ARPCTransmissionBuffer struct {
+	Name    string
+	Id      *gouuidtools.UUID
+	Payload ARPCBufferI
+}
+
+// transmission is named set of buffers
+type ARPCTransmissionI interface {
+	// BufferIds and BufferNames are filled by controller
+	GetInfo() *ARPCTransmissionInfo
+	GetBuffers() []*ARPCTransmissionBuffer
+}
+
+var _ ARPCTransmissionI = &ARPCTransmissionMem{}
+var _ ARPCFinishNotifierI = &ARPCTransmissionMem{}
+
+// thread-safe in-memory transmission
+type ARPCTransmissionMem struct {
+	mtx *sync.Mutex
+
+	info    ARPCTransmissionInfo
+	buffers []*ARPCTransmissionBuffer
+
+	on_finished xARPCFinishCallbacks
 }
 
-type ARPCTransmissionI interface{}
+func NewARPCTransmissionMem(
+	human_title string,
+	human_description string,
+) *ARPCTransmissionMem {
+	self := new(ARPCTransmissionMem)
+	self.mtx = new(sync.Mutex)
+	self.info.HumanTitle = human_title
+	self.info.HumanDescription = human_description
+	self.info.State = ARPCTransmissionStateOpen
+	return self
+}
+
+func (self *ARPCTransmissionMem) GetInfo() *ARPCTransmissionInfo {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	ret := self.info
+	return &ret
+}
+
+func (self *ARPCTransmissionMem) GetBuffers() []*ARPCTransmissionBuffer {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	return append([]*ARPCTransmissionBuffer{}, self.buffers...)
+}
+
+// buffers can be added only while transmission is open.
+// buffer_id may be nil
+func (self *ARPCTransmissionMem) AddBuffer(
+	name string,
+	buffer_id *gouuidtools.UUID,
+	buffer ARPCBufferI,
+) error {
+	if buffer == nil {
+		return errors.New("buffer is nil")
+	}
+
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.info.State != ARPCTransmissionStateOpen {
+		return errors.New("transmission isn't open")
+	}
+
+	for _, i := range self.buffers {
+		if i.Name == name {
+			return errors.New("buffer with this name already exists")
+		}
+	}
+
+	self.buffers = append(
+		self.buffers,
+		&ARPCTransmissionBuffer{
+			Name:    name,
+			Id:      buffer_id,
+			Payload: buffer,
+		},
+	)
+
+	return nil
+}
+
+func (self *ARPCTransmissionMem) GetState() ARPCTransmissionState {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	return self.info.State
+}
+
+func (self *ARPCTransmissionMem) setState(state ARPCTransmissionState) error {
+	self.mtx.Lock()
+
+	if self.info.State != ARPCTransmissionStateOpen {
+		self.mtx.Unlock()
+		return errors.New("transmission isn't open")
+	}
+
+	self.info.State = state
+
+	// aborted transmission isn't finished, so callbacks are just dropped
+	on_finished := self.on_finished.take()
+
+	self.mtx.Unlock()
+
+	if state == ARPCTransmissionStateFinished {
+		for _, f := range on_finished {
+			f()
+		}
+	}
+
+	return nil
+}
+
+func (self *ARPCTransmissionMem) OnFinished(f func()) func() {
+	self.mtx.Lock()
+	defer self.mtx.Unlock()
+
+	if self.info.State != ARPCTransmissionStateOpen {
+		return func() {}
+	}
+
+	id := self.on_finished.add(f)
+
+	return func() {
+		self.mtx.Lock()
+		defer self.mtx.Unlock()
+		self.on_finished.remove(id)
+	}
+}
+
+func (self *ARPCTransmissionMem) Finish() error {
+	return self.setState(ARPCTransmissionStateFinished)
+}
+
+func (self *ARPCTransmissionMem) Abort() error {
+	return self.setState(ARPCTransmissionStateAborted)
+}
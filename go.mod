@@ -7,12 +7,13 @@ require (
 	github.com/AnimusPEXUS/gorecursionguard v0.0.0-20230721164047-3900188c3f12
 	github.com/AnimusPEXUS/goreentrantlock v0.0.0-20230722175424-235503e905b0
 	github.com/AnimusPEXUS/gouuidtools v0.0.0-20230722031440-125d4120438a
-	github.com/AnimusPEXUS/goworker v0.0.0-20230722022549-6b2d4e08cd4e
 	github.com/AnimusPEXUS/utils v0.0.0-20230722023513-9799ab409870
+	github.com/fxamacker/cbor/v2 v2.7.0
 	github.com/mitchellh/mapstructure v1.5.0
 )
 
 require (
 	github.com/AnimusPEXUS/golockercheckable v0.0.0-20230722172911-98279345df8b // indirect
 	github.com/AnimusPEXUS/goroutineid v0.0.0-20230720133607-c9ed2d3b2260 // indirect
+	github.com/x448/float16 v0.8.4 // indirect
 )
@@ -2,6 +2,8 @@ package goarpcsolution
 
 import (
 	"io"
+
+	"github.com/AnimusPEXUS/gouuidtools"
 )
 
 type ReadWriteSeekCloser interface {
@@ -10,3 +12,34 @@ type ReadWriteSeekCloser interface {
 	io.Closer
 	io.Seeker
 }
+
+// nil UUIDs are equal only to nil UUIDs
+func uuidsEqual(a, b *gouuidtools.UUID) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return a.Format() == b.Format()
+}
+
+// kinds of objects, which can be referred by id on remote side
+type ARPCObjectType string
+
+const (
+	ARPCObjectTypeCall            ARPCObjectType = "call"
+	ARPCObjectTypeBuffer          ARPCObjectType = "buffer"
+	ARPCObjectTypeTransmission    ARPCObjectType = "transmission"
+	ARPCObjectTypeListeningSocket ARPCObjectType = "listening_socket"
+	ARPCObjectTypeConnectedSocket ARPCObjectType = "connected_socket"
+)
+
+// why object was removed. passed with ObjectRemoved notification
+type ARPCObjectRemoveReason string
+
+const (
+	// object is closed explicitly (by CallClose(), SocketClose()) or
+	// together with call which owned it
+	ARPCObjectRemoveReasonClosed ARPCObjectRemoveReason = "closed"
+
+	// object's lease is out
+	ARPCObjectRemoveReasonExpired ARPCObjectRemoveReason = "expired"
+)